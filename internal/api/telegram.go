@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,6 +17,8 @@ import (
 
 const (
 	TelegramWebhookPath = "/telegram/webhook"
+	processingMessage   = "Got your receipt, give me a moment to read it…"
+	receiptErrorMessage = "Sorry, I couldn't read that receipt."
 )
 
 type TelegramWebhook struct {
@@ -24,48 +27,124 @@ type TelegramWebhook struct {
 	OCR         ocr.ImageOCR
 }
 
+// splitBotCommands is registered with Telegram on startup so clients show
+// them in the bot's command menu.
+var splitBotCommands = []telegram.BotCommand{
+	{Command: "start", Description: "Say hello and register with the bot"},
+	{Command: "help", Description: "List available commands"},
+	{Command: "groupid", Description: "Show this chat's ID"},
+	{Command: "balance", Description: "Show each person's net balance"},
+	{Command: "settle", Description: "Record a payment: /settle @user amount"},
+	{Command: "history", Description: "Show recent expenses: /history [n]"},
+	{Command: "export", Description: "Export expenses: /export csv|json"},
+	{Command: "addsplit", Description: "Add someone to the pending split: /addsplit @user"},
+}
+
 func NewTelegramWebhook() *TelegramWebhook {
-	tw := &TelegramWebhook{
-		TelegramAPI: telegram.NewTelegramAPI(TelegramWebhookPath),
+	ocrRegistry, err := ocr.NewRegistry(ocr.ConfigFromEnv())
+	if err != nil {
+		panic(err)
+	}
+
+	telegramAPI := telegram.NewTelegramAPI(TelegramWebhookPath)
+	if err := telegramAPI.SetMyCommands("startup", splitBotCommands); err != nil {
+		log.Printf("failed to register bot commands: %v", err)
+	}
+
+	return &TelegramWebhook{
+		TelegramAPI: telegramAPI,
 		SplitBot:    splitbot.NewBot(),
-		OCR:         ocr.NewMistralOCR(),
+		OCR:         ocrRegistry,
 	}
-	return tw
 }
 
 func (t *TelegramWebhook) TelegramWebhook(c *gin.Context) {
 	requestID := requestid.Get(c)
-	message, chatID, err := t.preProcessMsg(c)
+	update, err := t.parseBody(c)
 	if err != nil {
-		log.Printf("create splitbot message failed %v", err)
+		log.Printf("parse telegram update failed %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	if message == nil {
-		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+	status, err := t.HandleUpdate(requestID, update)
+	if err != nil {
+		log.Printf("handle telegram update failed %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
 		return
 	}
 
-	response, err := t.SplitBot.HandleMessage(requestID, message)
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
+
+// HandleUpdate processes a single Update regardless of how it arrived
+// (webhook POST body or LongPoll), so both transports share the same
+// message handling.
+func (t *TelegramWebhook) HandleUpdate(requestID string, update *telegram.Update) (string, error) {
+	if update.CallbackQuery != nil {
+		return t.handleCallbackQuery(requestID, update.CallbackQuery)
+	}
+
+	if update.Message == nil {
+		return "ignored", nil
+	}
+
+	msg := buildMessage(update.Message)
+
+	if len(update.Message.Photo) > 0 || update.Message.Document != nil {
+		if err := t.handleImageMessageAsync(requestID, update.Message, msg); err != nil {
+			return "", fmt.Errorf("failed to process receipt: %w", err)
+		}
+		return "processing", nil
+	}
+
+	if splitbot.IsCommand(msg.Text) {
+		result, err := t.SplitBot.HandleCommand(requestID, msg)
+		if err != nil {
+			return "", fmt.Errorf("handle command failed: %w", err)
+		}
+		return t.sendCommandResult(requestID, msg.ChatID, result)
+	}
+
+	response, err := t.SplitBot.HandleMessage(requestID, msg)
 	if err != nil {
-		log.Printf("handle message failed %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
-		return
+		return "", fmt.Errorf("handle message failed: %w", err)
 	}
 
-	if err := t.TelegramAPI.SendMessage(requestID, chatID, response); err != nil {
-		log.Printf("telegram webhook: failed to send OCR result: %v", err)
-		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to send OCR result"})
-		return
+	if _, err := t.TelegramAPI.SendMessage(requestID, msg.ChatID, response); err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return "ok", nil
+}
+
+// sendCommandResult sends a slash command's reply: a document upload for
+// /export, or otherwise a plain text message. For /addsplit the split
+// confirmation message is also refreshed in place before the text is sent.
+func (t *TelegramWebhook) sendCommandResult(requestID string, chatID int64, result *splitbot.CommandResult) (string, error) {
+	if result.Document != nil {
+		if _, err := t.TelegramAPI.SendDocument(requestID, chatID, result.Document.Filename, bytes.NewReader(result.Document.Content), result.Text); err != nil {
+			return "", fmt.Errorf("failed to send export document: %w", err)
+		}
+		return "ok", nil
+	}
+
+	if result.PendingSplit != nil {
+		var opts []telegram.EditMessageTextOption
+		if result.PendingSplit.Keyboard != nil {
+			opts = append(opts, telegram.WithEditReplyMarkup(*result.PendingSplit.Keyboard))
+		}
+		if err := t.TelegramAPI.EditMessageText(requestID, chatID, result.PendingSplit.MessageID, result.PendingSplit.Text, opts...); err != nil {
+			log.Printf("requestID=%s failed to refresh split message: %v", requestID, err)
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	return
+	if _, err := t.TelegramAPI.SendMessage(requestID, chatID, result.Text); err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+	return "ok", nil
 }
 
 func (t *TelegramWebhook) parseBody(c *gin.Context) (*telegram.Update, error) {
@@ -82,88 +161,140 @@ func (t *TelegramWebhook) parseBody(c *gin.Context) (*telegram.Update, error) {
 	return &update, nil
 }
 
-func (t *TelegramWebhook) handlePhotoMessage(msg *splitbot.Message, photos []telegram.Photo, caption string, requestID string) error {
-	highestResPhoto := photos[len(photos)-1]
+// buildMessage converts a telegram.Message into a splitbot.Message,
+// preferring the caption as the text when the message carries an image.
+func buildMessage(message *telegram.Message) *splitbot.Message {
+	msg := &splitbot.Message{
+		ChatID: message.Chat.ID,
+		Text:   message.Text,
+	}
+	if message.From != nil {
+		msg.From = splitbot.User{
+			ID:       message.From.ID,
+			Username: message.From.Username,
+		}
+	}
+	if message.Caption != "" {
+		msg.Text = message.Caption
+	}
+	return msg
+}
+
+// imageFileRef returns the file_id and file_unique_id of the photo or
+// document attached to message.
+func imageFileRef(message *telegram.Message) (fileID string, fileUniqueID string, err error) {
+	if len(message.Photo) > 0 {
+		highestResPhoto := message.Photo[len(message.Photo)-1]
+		return highestResPhoto.FileID, highestResPhoto.FileUniqueID, nil
+	}
+	if message.Document != nil {
+		return message.Document.FileID, message.Document.FileUniqueID, nil
+	}
+	return "", "", fmt.Errorf("message has no photo or document")
+}
 
-	// Get the image URL
-	url, err := t.TelegramAPI.GetImageUrl(requestID, highestResPhoto.FileID)
+// handleImageMessageAsync acknowledges a photo/document upload with a
+// "processing…" reply, then extracts and hands off the receipt in the
+// background, editing the reply in place once it's done. OCR can take
+// several seconds, long enough that blocking the webhook response risks
+// Telegram retrying the delivery.
+func (t *TelegramWebhook) handleImageMessageAsync(requestID string, message *telegram.Message, msg *splitbot.Message) error {
+	fileID, fileUniqueID, err := imageFileRef(message)
 	if err != nil {
-		return errors.New("failed to get image URL")
+		return err
 	}
 
-	// Extract text from image using OCR
-	extractedText, err := t.OCR.ExtractTextFromImage(requestID, url)
+	messageID, err := t.TelegramAPI.SendMessage(requestID, msg.ChatID, processingMessage)
 	if err != nil {
-		log.Printf("OCR extraction failed for photo: %v", err)
-		extractedText = "OCR extraction failed"
+		return fmt.Errorf("failed to send processing message: %w", err)
 	}
 
-	msg.Image = &splitbot.Image{
-		FileID:        highestResPhoto.FileID,
-		Url:           url,
-		ExtractedText: extractedText,
-	}
-	if len(caption) > 0 {
-		msg.Text = caption
-	}
+	go t.processImageMessage(requestID, msg, fileID, fileUniqueID, messageID)
 	return nil
 }
 
-func (t *TelegramWebhook) handleDocumentMessage(msg *splitbot.Message, document *telegram.Document, caption string, requestID string) error {
-	// Get the image URL
-	url, err := t.TelegramAPI.GetImageUrl(requestID, document.FileID)
+// processImageMessage OCRs the uploaded receipt, parses it into
+// structured line items, and turns the "processing…" placeholder into a
+// split confirmation prompt with an inline keyboard, rather than letting
+// the agent guess at participants and split mode.
+func (t *TelegramWebhook) processImageMessage(requestID string, msg *splitbot.Message, fileID string, fileUniqueID string, messageID int) {
+	url, err := t.TelegramAPI.GetImageUrl(requestID, fileID)
 	if err != nil {
-		return errors.New("failed to get image URL")
+		log.Printf("requestID=%s failed to get image url: %v", requestID, err)
+		t.editWithError(requestID, msg.ChatID, messageID)
+		return
 	}
 
-	// Extract text from image using OCR
-	extractedText, err := t.OCR.ExtractTextFromImage(requestID, url)
+	result, err := t.OCR.ExtractTextFromImage(requestID, fileUniqueID, url)
 	if err != nil {
-		log.Printf("OCR extraction failed for document: %v", err)
-		extractedText = "OCR extraction failed"
+		log.Printf("requestID=%s OCR extraction failed: %v", requestID, err)
+		t.editWithError(requestID, msg.ChatID, messageID)
+		return
+	}
+
+	parsed, err := t.SplitBot.ParseReceipt(requestID, result.Text)
+	if err != nil {
+		log.Printf("requestID=%s failed to parse receipt: %v", requestID, err)
+		t.editWithError(requestID, msg.ChatID, messageID)
+		return
 	}
 
-	msg.Image = &splitbot.Image{
-		FileID:        document.FileID,
-		Url:           url,
-		ExtractedText: extractedText,
+	text, keyboard, pendingSplitID, err := t.SplitBot.ProposeSplit(requestID, msg.ChatID, msg.From, parsed)
+	if err != nil {
+		log.Printf("requestID=%s failed to propose split: %v", requestID, err)
+		t.editWithError(requestID, msg.ChatID, messageID)
+		return
 	}
-	if len(caption) > 0 {
-		msg.Text = caption
+
+	if err := t.SplitBot.SetPendingSplitMessageID(pendingSplitID, messageID); err != nil {
+		log.Printf("requestID=%s failed to record pending split message id: %v", requestID, err)
+		t.editWithError(requestID, msg.ChatID, messageID)
+		return
+	}
+
+	var opts []telegram.EditMessageTextOption
+	if keyboard != nil {
+		opts = append(opts, telegram.WithEditReplyMarkup(*keyboard))
+	}
+	if err := t.TelegramAPI.EditMessageText(requestID, msg.ChatID, messageID, text, opts...); err != nil {
+		log.Printf("requestID=%s failed to show split confirmation: %v", requestID, err)
 	}
-	return nil
 }
 
-func (t *TelegramWebhook) preProcessMsg(c *gin.Context) (*splitbot.Message, int64, error) {
-	update, err := t.parseBody(c)
-	if err != nil {
-		return nil, 0, err
+func (t *TelegramWebhook) editWithError(requestID string, chatID int64, messageID int) {
+	if err := t.TelegramAPI.EditMessageText(requestID, chatID, messageID, receiptErrorMessage); err != nil {
+		log.Printf("requestID=%s failed to edit message with error: %v", requestID, err)
 	}
-	if update.Message == nil {
-		return nil, 0, nil
+}
+
+// handleCallbackQuery applies an inline keyboard button press to its
+// pending split, acknowledges the press, and updates the message with
+// the new state (or the final receipt, once confirmed).
+func (t *TelegramWebhook) handleCallbackQuery(requestID string, cbq *telegram.CallbackQuery) (string, error) {
+	if cbq.Message == nil {
+		return "", fmt.Errorf("callback query missing message")
 	}
-	msg := &splitbot.Message{
-		Text: update.Message.Text,
+
+	text, keyboard, done, err := t.SplitBot.HandleCallback(requestID, cbq)
+	if err != nil {
+		_ = t.TelegramAPI.AnswerCallbackQuery(requestID, cbq.ID, "Something went wrong.")
+		return "", fmt.Errorf("failed to handle callback: %w", err)
 	}
-	requestID := requestid.Get(c)
-	if update.Message.From != nil {
-		msg.From = splitbot.User{
-			ID:       update.Message.From.ID,
-			Username: update.Message.From.Username,
-		}
+
+	if err := t.TelegramAPI.AnswerCallbackQuery(requestID, cbq.ID, ""); err != nil {
+		log.Printf("requestID=%s failed to answer callback query: %v", requestID, err)
 	}
-	if len(update.Message.Photo) > 0 {
-		if err := t.handlePhotoMessage(msg, update.Message.Photo, update.Message.Caption, requestID); err != nil {
-			return nil, 0, err
-		}
-		// the message can either contain image or document
-		return msg, update.Message.Chat.ID, nil
+
+	var opts []telegram.EditMessageTextOption
+	if keyboard != nil {
+		opts = append(opts, telegram.WithEditReplyMarkup(*keyboard))
 	}
-	if update.Message.Document != nil {
-		if err := t.handleDocumentMessage(msg, update.Message.Document, update.Message.Caption, requestID); err != nil {
-			return nil, 0, err
-		}
-		return msg, update.Message.Chat.ID, nil
+	if err := t.TelegramAPI.EditMessageText(requestID, cbq.Message.Chat.ID, cbq.Message.MessageID, text, opts...); err != nil {
+		return "", fmt.Errorf("failed to update split message: %w", err)
+	}
+
+	if done {
+		return "confirmed", nil
 	}
-	return msg, update.Message.Chat.ID, nil
+	return "updated", nil
 }
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// User represents a Telegram user that has interacted with the bot in at
+// least one chat. Users are keyed by username rather than TelegramID:
+// tools and commands only ever have a username to go on (the agent reads
+// usernames out of plain-English messages, not numeric IDs), so
+// TelegramID is not a lookup key, and GetOrCreateUser leaves it unset.
+type User struct {
+	gorm.Model
+	TelegramID int64
+	Username   string `gorm:"uniqueIndex"`
+}
+
+// Chat represents a Telegram chat (private or group) the bot is tracking
+// state for, e.g. conversation history and expenses.
+type Chat struct {
+	gorm.Model
+	TelegramID int64 `gorm:"uniqueIndex;not null"`
+	Type       string
+	Title      string
+}
+
+// ConversationMessage is a single turn of the conversation between a chat
+// and the assistant, used to back the agent's memory.
+type ConversationMessage struct {
+	gorm.Model
+	ChatID  uint `gorm:"index;not null"`
+	Chat    Chat
+	Role    string `gorm:"not null"` // "human" or "ai"
+	Content string `gorm:"type:text;not null"`
+}
+
+// Expense is a single shared expense added to a chat, e.g. a restaurant
+// bill or a receipt.
+type Expense struct {
+	gorm.Model
+	ChatID       uint `gorm:"index;not null"`
+	Chat         Chat
+	PayerID      uint `gorm:"not null"`
+	Payer        User
+	Description  string
+	Amount       float64 `gorm:"not null"`
+	Currency     string  `gorm:"not null;default:USD"`
+	Participants []ExpenseParticipant
+}
+
+// ExpenseParticipant is one user's share of an Expense, either an equal
+// split or a weighted share.
+type ExpenseParticipant struct {
+	gorm.Model
+	ExpenseID uint `gorm:"index;not null"`
+	UserID    uint `gorm:"index;not null"`
+	User      User
+	Weight    float64 `gorm:"not null;default:1"`
+	Share     float64 `gorm:"not null"`
+}
+
+// PendingSplit holds an in-progress split confirmation between the time
+// a receipt is parsed and the user taps "Confirm", keyed by the Telegram
+// message ID of the confirmation prompt so a button press can find the
+// split it belongs to.
+type PendingSplit struct {
+	gorm.Model
+	ChatID       uint `gorm:"index;not null"`
+	Chat         Chat
+	MessageID    int  `gorm:"index"`
+	CreatedByID  uint `gorm:"not null"`
+	CreatedBy    User `gorm:"foreignKey:CreatedByID"`
+	Payer        string
+	Description  string
+	Amount       float64
+	Currency     string `gorm:"not null;default:USD"`
+	SplitMode    string `gorm:"not null;default:equal"`
+	Participants string `gorm:"type:text;not null"` // JSON-encoded map[username]bool
+	Confirmed    bool
+}
+
+// Settlement records a payment made between two users in a chat to clear
+// part or all of their outstanding balance.
+type Settlement struct {
+	gorm.Model
+	ChatID     uint `gorm:"index;not null"`
+	Chat       Chat
+	FromUserID uint      `gorm:"not null"`
+	FromUser   User      `gorm:"foreignKey:FromUserID"`
+	ToUserID   uint      `gorm:"not null"`
+	ToUser     User      `gorm:"foreignKey:ToUserID"`
+	Amount     float64   `gorm:"not null"`
+	Currency   string    `gorm:"not null;default:USD"`
+	SettledAt  time.Time `gorm:"not null"`
+}
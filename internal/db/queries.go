@@ -0,0 +1,102 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// GetOrCreateChat resolves a Telegram chat ID to its internal Chat row,
+// creating it on first contact.
+func GetOrCreateChat(telegramChatID int64) (*Chat, error) {
+	database := GetDB()
+	var chat Chat
+	err := database.Where("telegram_id = ?", telegramChatID).First(&chat).Error
+	if err == nil {
+		return &chat, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to look up chat: %w", err)
+	}
+
+	chat = Chat{TelegramID: telegramChatID}
+	if err := database.Create(&chat).Error; err != nil {
+		return nil, fmt.Errorf("failed to create chat: %w", err)
+	}
+	return &chat, nil
+}
+
+// GetOrCreateUser resolves a Telegram username to its User row, creating
+// it on first contact. The username is matched exactly as given, so
+// callers strip any "@" prefix before calling.
+func GetOrCreateUser(username string) (*User, error) {
+	database := GetDB()
+	var user User
+	err := database.Where("username = ?", username).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to look up user %q: %w", username, err)
+	}
+
+	user = User{Username: username}
+	if err := database.Create(&user).Error; err != nil {
+		return nil, fmt.Errorf("failed to create user %q: %w", username, err)
+	}
+	return &user, nil
+}
+
+// ListKnownChatUsers returns every user who has previously appeared in
+// this chat's expenses or settlements, as a payer, a participant, or
+// either side of a settlement. It's used to offer a new split's other
+// members as toggle candidates without the uploader having to type each
+// name.
+func ListKnownChatUsers(chatID uint) ([]User, error) {
+	database := GetDB()
+	seen := map[uint]User{}
+
+	var expenses []Expense
+	if err := database.Preload("Payer").Preload("Participants.User").
+		Where("chat_id = ?", chatID).Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load expenses: %w", err)
+	}
+	for _, expense := range expenses {
+		seen[expense.Payer.ID] = expense.Payer
+		for _, participant := range expense.Participants {
+			seen[participant.User.ID] = participant.User
+		}
+	}
+
+	var settlements []Settlement
+	if err := database.Preload("FromUser").Preload("ToUser").
+		Where("chat_id = ?", chatID).Find(&settlements).Error; err != nil {
+		return nil, fmt.Errorf("failed to load settlements: %w", err)
+	}
+	for _, settlement := range settlements {
+		seen[settlement.FromUser.ID] = settlement.FromUser
+		seen[settlement.ToUser.ID] = settlement.ToUser
+	}
+
+	users := make([]User, 0, len(seen))
+	for _, user := range seen {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// FindActivePendingSplit returns the most recent unconfirmed PendingSplit
+// for a chat, or gorm.ErrRecordNotFound if there isn't one. Each receipt
+// upload starts its own PendingSplit, so a chat can have several awaiting
+// confirmation; a follow-up command (e.g. adding a participant) applies to
+// the newest one.
+func FindActivePendingSplit(chatID uint) (*PendingSplit, error) {
+	var pending PendingSplit
+	err := GetDB().Where("chat_id = ? AND confirmed = ?", chatID, false).
+		Order("id desc").First(&pending).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
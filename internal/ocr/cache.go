@@ -0,0 +1,48 @@
+package ocr
+
+import "sync"
+
+var _ ImageOCR = &CachingOCR{}
+
+// CachingOCR wraps another ImageOCR and caches results by Telegram
+// file_unique_id, so a user re-sending (or forwarding) the same photo
+// doesn't re-hit a paid OCR API.
+type CachingOCR struct {
+	inner ImageOCR
+
+	mu    sync.Mutex
+	cache map[string]Result
+}
+
+// NewCachingOCR wraps inner with an in-memory cache keyed by
+// file_unique_id.
+func NewCachingOCR(inner ImageOCR) *CachingOCR {
+	return &CachingOCR{
+		inner: inner,
+		cache: make(map[string]Result),
+	}
+}
+
+// ExtractTextFromImage implements ImageOCR.
+func (c *CachingOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
+	if fileUniqueID != "" {
+		c.mu.Lock()
+		cached, ok := c.cache[fileUniqueID]
+		c.mu.Unlock()
+		if ok {
+			return cached, nil
+		}
+	}
+
+	result, err := c.inner.ExtractTextFromImage(requestID, fileUniqueID, imageURL)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if fileUniqueID != "" {
+		c.mu.Lock()
+		c.cache[fileUniqueID] = result
+		c.mu.Unlock()
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package ocr
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+var _ ImageOCR = &ChainOCR{}
+
+// defaultConfidenceThreshold is the minimum confidence a provider's
+// result needs to be accepted before ChainOCR falls through to the next
+// provider.
+const defaultConfidenceThreshold = 0.5
+
+// ChainOCR tries each provider in order, returning the first result whose
+// text is non-empty and whose confidence clears the threshold, so a
+// provider that's down or returns garbage doesn't block a better one
+// further down the chain.
+type ChainOCR struct {
+	providers []ImageOCR
+	threshold float64
+}
+
+// NewChainOCR builds a ChainOCR over the given providers, tried in order.
+func NewChainOCR(providers ...ImageOCR) *ChainOCR {
+	return &ChainOCR{
+		providers: providers,
+		threshold: defaultConfidenceThreshold,
+	}
+}
+
+// ExtractTextFromImage implements ImageOCR.
+func (c *ChainOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
+	var lastErr error
+	for _, provider := range c.providers {
+		result, err := provider.ExtractTextFromImage(requestID, fileUniqueID, imageURL)
+		if err != nil {
+			log.Printf("requestID=%s ocr provider failed, trying next: %v", requestID, err)
+			lastErr = err
+			continue
+		}
+		if strings.TrimSpace(result.Text) != "" && result.Confidence >= c.threshold {
+			return result, nil
+		}
+		lastErr = fmt.Errorf("low-confidence result (confidence=%.2f)", result.Confidence)
+	}
+
+	if len(c.providers) == 0 {
+		return Result{}, fmt.Errorf("no OCR providers configured")
+	}
+	return Result{}, fmt.Errorf("all OCR providers failed or returned low-confidence results: %w", lastErr)
+}
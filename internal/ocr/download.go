@@ -0,0 +1,58 @@
+package ocr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// imageDownloader fetches a remote image to a local temp file for OCR
+// backends that need to operate on a file rather than a URL.
+type imageDownloader interface {
+	// download returns the local path to the downloaded image and a
+	// cleanup func that removes it.
+	download(requestID string, imageURL string) (path string, cleanup func(), err error)
+}
+
+var _ imageDownloader = &httpImageDownloader{}
+
+type httpImageDownloader struct {
+	client *http.Client
+}
+
+func newHTTPImageDownloader() *httpImageDownloader {
+	return &httpImageDownloader{client: http.DefaultClient}
+}
+
+func (d *httpImageDownloader) download(requestID string, imageURL string) (string, func(), error) {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build download request: %w", err)
+	}
+	req.Header.Set("X-Request-ID", requestID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", nil, fmt.Errorf("image download returned non-2xx status: %d", resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp("", "split-bot-ocr-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmp.Close()
+
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
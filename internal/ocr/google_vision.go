@@ -0,0 +1,108 @@
+package ocr
+
+import (
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var _ ImageOCR = &GoogleVisionOCR{}
+
+// googleVisionRequest is the payload for the Vision API's images:annotate
+// endpoint, requesting text detection on a single remote image.
+type googleVisionRequest struct {
+	Requests []googleVisionImageRequest `json:"requests"`
+}
+
+type googleVisionImageRequest struct {
+	Image    googleVisionImage     `json:"image"`
+	Features []googleVisionFeature `json:"features"`
+}
+
+type googleVisionImage struct {
+	Source googleVisionImageSource `json:"source"`
+}
+
+type googleVisionImageSource struct {
+	ImageURI string `json:"imageUri"`
+}
+
+type googleVisionFeature struct {
+	Type string `json:"type"`
+}
+
+type googleVisionResponse struct {
+	Responses []struct {
+		FullTextAnnotation struct {
+			Text string `json:"text"`
+		} `json:"fullTextAnnotation"`
+		TextAnnotations []struct {
+			Description string  `json:"description"`
+			Confidence  float64 `json:"confidence"`
+		} `json:"textAnnotations"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"responses"`
+}
+
+// GoogleVisionOCR calls the Google Cloud Vision API's TEXT_DETECTION
+// feature.
+type GoogleVisionOCR struct {
+	apiKey string
+	client *resty.Client
+}
+
+// NewGoogleVisionOCR builds a GoogleVisionOCR using a Vision API key.
+func NewGoogleVisionOCR(apiKey string) *GoogleVisionOCR {
+	return &GoogleVisionOCR{
+		apiKey: apiKey,
+		client: resty.New(),
+	}
+}
+
+// ExtractTextFromImage implements ImageOCR.
+func (g *GoogleVisionOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
+	request := googleVisionRequest{
+		Requests: []googleVisionImageRequest{{
+			Image:    googleVisionImage{Source: googleVisionImageSource{ImageURI: imageURL}},
+			Features: []googleVisionFeature{{Type: "TEXT_DETECTION"}},
+		}},
+	}
+
+	var response googleVisionResponse
+	resp, err := g.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Request-ID", requestID).
+		SetQueryParam("key", g.apiKey).
+		SetBody(request).
+		SetResult(&response).
+		Post("https://vision.googleapis.com/v1/images:annotate")
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to call google vision: %w", err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return Result{}, fmt.Errorf("google vision returned non-2xx status: %d", resp.StatusCode())
+	}
+	if len(response.Responses) != 1 {
+		return Result{}, fmt.Errorf("expected exactly 1 response, got %d", len(response.Responses))
+	}
+
+	annotation := response.Responses[0]
+	if annotation.Error != nil {
+		return Result{}, fmt.Errorf("google vision error: %s", annotation.Error.Message)
+	}
+
+	// The first entry in textAnnotations is the whole-image annotation
+	// and carries the overall confidence; fullTextAnnotation has the
+	// cleaner, reading-order text.
+	confidence := 0.0
+	if len(annotation.TextAnnotations) > 0 {
+		confidence = annotation.TextAnnotations[0].Confidence
+	}
+	if confidence == 0 && annotation.FullTextAnnotation.Text != "" {
+		confidence = 1.0
+	}
+
+	return Result{Text: annotation.FullTextAnnotation.Text, Confidence: confidence}, nil
+}
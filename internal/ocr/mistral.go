@@ -87,8 +87,10 @@ func NewMistralOCR(apiKey string) *MistralOCR {
 }
 
 // ExtractTextFromImage calls the Mistral OCR API with the given image URL
-// and returns the markdown text extracted from the image
-func (m *MistralOCR) ExtractTextFromImage(requestID string, imageURL string) (string, error) {
+// and returns the markdown text extracted from the image. Mistral's OCR
+// API doesn't report a confidence score, so Confidence is 1.0 whenever
+// text was returned.
+func (m *MistralOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
 	// Prepare the request payload
 	request := MistralOCRRequest{
 		Model: "mistral-ocr-latest",
@@ -109,7 +111,7 @@ func (m *MistralOCR) ExtractTextFromImage(requestID string, imageURL string) (st
 		Post("https://api.mistral.ai/v1/ocr")
 
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return Result{}, fmt.Errorf("failed to make request: %w", err)
 	}
 
 	// Handle different status codes
@@ -118,31 +120,35 @@ func (m *MistralOCR) ExtractTextFromImage(requestID string, imageURL string) (st
 		// Parse successful response
 		var ocrResponse MistralOCRResponse
 		if err := json.Unmarshal(resp.Body(), &ocrResponse); err != nil {
-			return "", fmt.Errorf("failed to unmarshal response: %w", err)
+			return Result{}, fmt.Errorf("failed to unmarshal response: %w", err)
 		}
 
 		// Check if there's exactly one page
 		if len(ocrResponse.Pages) != 1 {
-			return "", fmt.Errorf("expected exactly 1 page, got %d", len(ocrResponse.Pages))
+			return Result{}, fmt.Errorf("expected exactly 1 page, got %d", len(ocrResponse.Pages))
 		}
 
-		// Return the markdown content from the first (and only) page
-		return ocrResponse.Pages[0].Markdown, nil
+		markdown := ocrResponse.Pages[0].Markdown
+		confidence := 0.0
+		if markdown != "" {
+			confidence = 1.0
+		}
+		return Result{Text: markdown, Confidence: confidence}, nil
 
 	case 442: // Validation error
 		// Parse validation error response
 		var validationError MistralValidationError
 		if err := json.Unmarshal(resp.Body(), &validationError); err != nil {
-			return "", fmt.Errorf("failed to unmarshal validation error: %w", err)
+			return Result{}, fmt.Errorf("failed to unmarshal validation error: %w", err)
 		}
 
 		// Extract error message from the first validation detail
 		if len(validationError.Detail) > 0 {
-			return "", fmt.Errorf("validation error: %s", validationError.Detail[0].Msg)
+			return Result{}, fmt.Errorf("validation error: %s", validationError.Detail[0].Msg)
 		}
-		return "", fmt.Errorf("validation error: unknown validation error")
+		return Result{}, fmt.Errorf("validation error: unknown validation error")
 
 	default:
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode(), string(resp.Body()))
+		return Result{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode(), string(resp.Body()))
 	}
 }
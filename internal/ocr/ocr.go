@@ -1,5 +1,17 @@
 package ocr
 
+// Result is the text an ImageOCR provider extracted from an image, along
+// with how confident the provider is in it. Providers that don't surface
+// a real confidence score report 1.0 for non-empty text and 0.0 for
+// empty text.
+type Result struct {
+	Text       string
+	Confidence float64
+}
+
+// ImageOCR extracts text from an image. fileUniqueID is the Telegram
+// file_unique_id of the image, used by CachingOCR to key its cache;
+// imageURL is where the image can be downloaded from.
 type ImageOCR interface {
-	ExtractTextFromImage(requestID string, imageURL string) (string, error)
+	ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error)
 }
@@ -0,0 +1,62 @@
+package ocr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+var _ ImageOCR = &OpenAIVisionOCR{}
+
+const openAIVisionPrompt = "Transcribe all text visible in this image exactly as it appears, preserving line breaks and table structure as markdown. Do not summarize or describe the image, only transcribe its text."
+
+// OpenAIVisionOCR uses a vision-capable chat model as a last-resort OCR
+// backend, useful when the dedicated OCR providers are down or
+// unconfigured.
+type OpenAIVisionOCR struct {
+	llm *openai.LLM
+}
+
+// NewOpenAIVisionOCR builds an OpenAIVisionOCR using the given token,
+// base URL, and vision-capable model.
+func NewOpenAIVisionOCR(token string, baseURL string, model string) (*OpenAIVisionOCR, error) {
+	llm, err := openai.New(
+		openai.WithToken(token),
+		openai.WithBaseURL(baseURL),
+		openai.WithModel(model),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init openai vision client: %w", err)
+	}
+	return &OpenAIVisionOCR{llm: llm}, nil
+}
+
+// ExtractTextFromImage implements ImageOCR. The model doesn't report a
+// confidence score, so Confidence is 1.0 whenever text was returned.
+func (o *OpenAIVisionOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
+	ctx := context.Background()
+	completion, err := o.llm.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, openAIVisionPrompt),
+		{
+			Role: llms.ChatMessageTypeHuman,
+			Parts: []llms.ContentPart{
+				llms.ImageURLPart(imageURL),
+			},
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("openai vision request failed: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return Result{}, fmt.Errorf("openai vision returned no choices")
+	}
+
+	text := completion.Choices[0].Content
+	confidence := 0.0
+	if text != "" {
+		confidence = 1.0
+	}
+	return Result{Text: text, Confidence: confidence}, nil
+}
@@ -0,0 +1,65 @@
+package ocr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config bundles the credentials each provider constructor needs. Fields
+// for providers not selected via OCR_PROVIDERS can be left empty.
+type Config struct {
+	MistralAPIKey      string
+	GoogleVisionAPIKey string
+	OpenAIToken        string
+	OpenAIBaseURL      string
+	OpenAIVisionModel  string
+}
+
+// ConfigFromEnv reads provider credentials from the environment.
+func ConfigFromEnv() Config {
+	return Config{
+		MistralAPIKey:      os.Getenv("MISTRAL_API_KEY"),
+		GoogleVisionAPIKey: os.Getenv("GOOGLE_VISION_API_KEY"),
+		OpenAIToken:        os.Getenv("OPENAI_TOKEN"),
+		OpenAIBaseURL:      os.Getenv("OPENAI_BASE_URL"),
+		OpenAIVisionModel:  os.Getenv("OPENAI_VISION_MODEL"),
+	}
+}
+
+// NewRegistry builds the OCR pipeline selected by the OCR_PROVIDERS env
+// var (comma-separated, e.g. "mistral,tesseract"), falling back to
+// mistral alone if unset. Providers are tried in order via ChainOCR, and
+// results are cached by Telegram file_unique_id via CachingOCR.
+func NewRegistry(cfg Config) (ImageOCR, error) {
+	names := os.Getenv("OCR_PROVIDERS")
+	if names == "" {
+		names = "mistral"
+	}
+
+	var providers []ImageOCR
+	for _, name := range strings.Split(names, ",") {
+		provider, err := newProvider(strings.TrimSpace(name), cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	return NewCachingOCR(NewChainOCR(providers...)), nil
+}
+
+func newProvider(name string, cfg Config) (ImageOCR, error) {
+	switch name {
+	case "mistral":
+		return NewMistralOCR(cfg.MistralAPIKey), nil
+	case "tesseract":
+		return NewTesseractOCR(), nil
+	case "google_vision", "google":
+		return NewGoogleVisionOCR(cfg.GoogleVisionAPIKey), nil
+	case "openai":
+		return NewOpenAIVisionOCR(cfg.OpenAIToken, cfg.OpenAIBaseURL, cfg.OpenAIVisionModel)
+	default:
+		return nil, fmt.Errorf("unknown OCR provider: %q", name)
+	}
+}
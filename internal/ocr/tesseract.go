@@ -0,0 +1,46 @@
+package ocr
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var _ ImageOCR = &TesseractOCR{}
+
+// TesseractOCR runs the local `tesseract` binary against a downloaded
+// image. It needs no API key, which makes it a reasonable first or
+// last link in a provider chain.
+type TesseractOCR struct {
+	client imageDownloader
+}
+
+// NewTesseractOCR builds a TesseractOCR that shells out to the `tesseract`
+// binary on PATH.
+func NewTesseractOCR() *TesseractOCR {
+	return &TesseractOCR{client: newHTTPImageDownloader()}
+}
+
+// ExtractTextFromImage implements ImageOCR. Tesseract doesn't report a
+// confidence score through its stdout-based CLI, so Confidence is 1.0
+// whenever text was returned.
+func (t *TesseractOCR) ExtractTextFromImage(requestID string, fileUniqueID string, imageURL string) (Result, error) {
+	imagePath, cleanup, err := t.client.download(requestID, imageURL)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer cleanup()
+
+	// tesseract writes its result to stdout when the output base is "-"
+	out, err := exec.Command("tesseract", imagePath, "-").Output()
+	if err != nil {
+		return Result{}, fmt.Errorf("tesseract failed: %w", err)
+	}
+
+	text := strings.TrimSpace(string(out))
+	confidence := 0.0
+	if text != "" {
+		confidence = 1.0
+	}
+	return Result{Text: text, Confidence: confidence}, nil
+}
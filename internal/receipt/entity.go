@@ -0,0 +1,22 @@
+package receipt
+
+// LineItem is a single purchased item on a receipt.
+type LineItem struct {
+	Name      string  `json:"name"`
+	Qty       float64 `json:"qty"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
+
+// Receipt is the structured representation of a receipt, parsed from OCR
+// markdown, that the agent uses to propose a split.
+type Receipt struct {
+	Merchant string     `json:"merchant"`
+	Date     string     `json:"date"`
+	Currency string     `json:"currency"`
+	Items    []LineItem `json:"items"`
+	Subtotal float64    `json:"subtotal"`
+	Tax      float64    `json:"tax"`
+	Tip      float64    `json:"tip"`
+	Total    float64    `json:"total"`
+}
@@ -0,0 +1,96 @@
+package receipt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+var _ Parser = &LLMParser{}
+
+// reconciliationTolerance is the fraction of the subtotal that the sum of
+// line item totals is allowed to deviate by before a parse is rejected as
+// unreliable.
+const reconciliationTolerance = 0.05
+
+const llmReceiptPrompt = `You are a receipt parsing assistant. Given OCR markdown of a receipt, extract the following JSON and return ONLY the JSON, with no surrounding prose:
+
+{
+  "merchant": string,
+  "date": string (ISO 8601 if present, else empty string),
+  "currency": string (ISO 4217 code, best guess if not stated explicitly),
+  "items": [{"name": string, "qty": number, "unit_price": number, "total": number}],
+  "subtotal": number,
+  "tax": number,
+  "tip": number,
+  "total": number
+}
+
+Receipt markdown:
+%s`
+
+// LLMParser extracts a Receipt by asking an LLM to return the Receipt
+// fields as JSON, then validates the response shape and reconciles line
+// items against the subtotal.
+type LLMParser struct {
+	llm *openai.LLM
+}
+
+// NewLLMParser builds an LLMParser reusing the same openai client the
+// conversational agent uses.
+func NewLLMParser(llm *openai.LLM) *LLMParser {
+	return &LLMParser{llm: llm}
+}
+
+type llmReceiptResponse struct {
+	Merchant string     `json:"merchant"`
+	Date     string     `json:"date"`
+	Currency string     `json:"currency"`
+	Items    []LineItem `json:"items"`
+	Subtotal float64    `json:"subtotal"`
+	Tax      float64    `json:"tax"`
+	Tip      float64    `json:"tip"`
+	Total    float64    `json:"total"`
+}
+
+// Parse implements Parser.
+func (p *LLMParser) Parse(requestID string, markdown string) (*Receipt, error) {
+	ctx := context.Background()
+	prompt := fmt.Sprintf(llmReceiptPrompt, markdown)
+
+	completion, err := llms.GenerateFromSinglePrompt(ctx, p.llm, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("llm receipt parse failed: %w", err)
+	}
+
+	var parsed llmReceiptResponse
+	if err := json.Unmarshal([]byte(completion), &parsed); err != nil {
+		return nil, fmt.Errorf("llm returned invalid JSON: %w", err)
+	}
+	if len(parsed.Items) == 0 {
+		return nil, fmt.Errorf("llm returned no line items")
+	}
+
+	itemSum := 0.0
+	for _, item := range parsed.Items {
+		itemSum += item.Total
+	}
+	if parsed.Subtotal > 0 && math.Abs(itemSum-parsed.Subtotal) > reconciliationTolerance*parsed.Subtotal {
+		return nil, fmt.Errorf("line items (%.2f) do not reconcile with subtotal (%.2f)", itemSum, parsed.Subtotal)
+	}
+
+	return &Receipt{
+		Merchant: parsed.Merchant,
+		Date:     parsed.Date,
+		Currency: parsed.Currency,
+		Items:    parsed.Items,
+		Subtotal: parsed.Subtotal,
+		Tax:      parsed.Tax,
+		Tip:      parsed.Tip,
+		Total:    parsed.Total,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package receipt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var _ Parser = &MarkdownParser{}
+
+// MarkdownParser extracts a Receipt from Mistral-style OCR markdown using
+// table rows for line items and "Label: value" lines for the summary
+// fields, with no external calls.
+type MarkdownParser struct{}
+
+func NewMarkdownParser() *MarkdownParser {
+	return &MarkdownParser{}
+}
+
+var summaryLineRE = regexp.MustCompile(`(?i)^(subtotal|tax|tip|gratuity|total)\s*[:|]?\s*\$?([0-9]+(?:\.[0-9]{1,2})?)\s*\|?$`)
+
+// Parse implements Parser.
+func (p *MarkdownParser) Parse(requestID string, markdown string) (*Receipt, error) {
+	r := &Receipt{Currency: "USD"}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Subtotal/Tax/Tip/Total are often rows in the same table as the
+		// line items, so this must be checked before parseTableRow or
+		// they'd be misread as items with a huge "price".
+		if label, value, ok := parseSummaryTableRow(line); ok {
+			applySummaryField(r, label, value)
+			continue
+		}
+
+		if item, ok := parseTableRow(line); ok {
+			r.Items = append(r.Items, item)
+			continue
+		}
+
+		if m := summaryLineRE.FindStringSubmatch(strings.ReplaceAll(line, "*", "")); m != nil {
+			value, err := strconv.ParseFloat(m[2], 64)
+			if err != nil {
+				continue
+			}
+			applySummaryField(r, strings.ToLower(m[1]), value)
+		}
+	}
+
+	if len(r.Items) == 0 {
+		return nil, fmt.Errorf("no line items found in receipt markdown")
+	}
+	return r, nil
+}
+
+func applySummaryField(r *Receipt, label string, value float64) {
+	switch label {
+	case "subtotal":
+		r.Subtotal = value
+	case "tax":
+		r.Tax = value
+	case "tip", "gratuity":
+		r.Tip = value
+	case "total":
+		r.Total = value
+	}
+}
+
+// summaryLabelRE matches a bare summary field name, e.g. "Subtotal". Cells
+// like "**Total**" or "Tax:" match once their "*", ":" and spaces are
+// trimmed, which parseSummaryTableRow does first.
+var summaryLabelRE = regexp.MustCompile(`(?i)^(subtotal|tax|tip|gratuity|total)$`)
+
+// parseSummaryTableRow extracts a summary field from a markdown table row
+// whose first cell names it, e.g. "| Subtotal | $20.00 |". Receipts often
+// put these in the same table as the line items, so this is checked ahead
+// of parseTableRow to keep them from being read as items.
+func parseSummaryTableRow(line string) (string, float64, bool) {
+	if !strings.HasPrefix(line, "|") || strings.Contains(line, "---") {
+		return "", 0, false
+	}
+	cols := strings.Split(strings.Trim(line, "|"), "|")
+	for i := range cols {
+		cols[i] = strings.TrimSpace(cols[i])
+	}
+	if len(cols) < 2 {
+		return "", 0, false
+	}
+
+	label := strings.Trim(cols[0], "*: ")
+	match := summaryLabelRE.FindStringSubmatch(label)
+	if match == nil {
+		return "", 0, false
+	}
+
+	valueStr := strings.TrimPrefix(strings.Trim(cols[len(cols)-1], "* "), "$")
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.ToLower(match[1]), value, true
+}
+
+// parseTableRow extracts a LineItem from a markdown table row, e.g.
+// "| Burger | 2 | $18.00 |", skipping header/separator rows.
+func parseTableRow(line string) (LineItem, bool) {
+	if !strings.HasPrefix(line, "|") || strings.Contains(line, "---") {
+		return LineItem{}, false
+	}
+	cols := strings.Split(strings.Trim(line, "|"), "|")
+	for i := range cols {
+		cols[i] = strings.TrimSpace(cols[i])
+	}
+	if len(cols) < 2 {
+		return LineItem{}, false
+	}
+
+	name := cols[0]
+	priceStr := strings.TrimPrefix(cols[len(cols)-1], "$")
+	total, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil || name == "" || strings.EqualFold(name, "item") {
+		return LineItem{}, false
+	}
+
+	item := LineItem{Name: name, Qty: 1, UnitPrice: total, Total: total}
+	if len(cols) >= 3 {
+		if qty, err := strconv.ParseFloat(cols[1], 64); err == nil && qty > 0 {
+			item.Qty = qty
+			item.UnitPrice = total / qty
+		}
+	}
+	return item, true
+}
@@ -0,0 +1,91 @@
+package receipt
+
+import "testing"
+
+func TestParseTableRow(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   LineItem
+		wantOk bool
+	}{
+		{"item with qty and price", "| Burger | 2 | $18.00 |", LineItem{Name: "Burger", Qty: 2, UnitPrice: 9, Total: 18}, true},
+		{"item with no qty column", "| Burger | $18.00 |", LineItem{Name: "Burger", Qty: 1, UnitPrice: 18, Total: 18}, true},
+		{"header row is skipped", "| Item | Qty | Price |", LineItem{}, false},
+		{"separator row is skipped", "| --- | --- | --- |", LineItem{}, false},
+		{"not a table row", "Subtotal: 20.00", LineItem{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTableRow(tt.line)
+			if ok != tt.wantOk {
+				t.Fatalf("parseTableRow(%q) ok = %v, want %v", tt.line, ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseTableRow(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSummaryTableRow(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLabel string
+		wantValue float64
+		wantOk    bool
+	}{
+		{"subtotal row", "| Subtotal | $20.00 |", "subtotal", 20, true},
+		{"bolded total row", "| **Total** | **23.60** |", "total", 23.60, true},
+		{"tip row with trailing colon", "| Tip: | 3.00 |", "tip", 3, true},
+		{"gratuity aliases tip", "| Gratuity | 3.00 |", "gratuity", 3, true},
+		{"regular line item is not a summary row", "| Burger | 2 | $18.00 |", "", 0, false},
+		{"not a table row", "Subtotal: 20.00", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, value, ok := parseSummaryTableRow(tt.line)
+			if ok != tt.wantOk {
+				t.Fatalf("parseSummaryTableRow(%q) ok = %v, want %v", tt.line, ok, tt.wantOk)
+			}
+			if ok && (label != tt.wantLabel || value != tt.wantValue) {
+				t.Errorf("parseSummaryTableRow(%q) = (%q, %v), want (%q, %v)", tt.line, label, value, tt.wantLabel, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestMarkdownParserParse(t *testing.T) {
+	p := NewMarkdownParser()
+
+	markdown := `
+| Item | Qty | Price |
+| --- | --- | --- |
+| Burger | 2 | $18.00 |
+| Fries | 1 | $5.00 |
+| Subtotal | | $23.00 |
+| Tax | | $2.00 |
+| Total | | $25.00 |
+`
+
+	receipt, err := p.Parse("req-1", markdown)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	if len(receipt.Items) != 2 {
+		t.Fatalf("len(receipt.Items) = %d, want 2", len(receipt.Items))
+	}
+	if receipt.Subtotal != 23 {
+		t.Errorf("receipt.Subtotal = %v, want 23", receipt.Subtotal)
+	}
+	if receipt.Tax != 2 {
+		t.Errorf("receipt.Tax = %v, want 2", receipt.Tax)
+	}
+	if receipt.Total != 25 {
+		t.Errorf("receipt.Total = %v, want 25", receipt.Total)
+	}
+}
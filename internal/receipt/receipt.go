@@ -0,0 +1,10 @@
+// Package receipt turns OCR markdown of a receipt into a structured
+// Receipt so the agent can propose a split instead of repeating the raw
+// OCR text back to the user.
+package receipt
+
+// Parser extracts a structured Receipt from the markdown produced by an
+// ocr.ImageOCR backend.
+type Parser interface {
+	Parse(requestID string, markdown string) (*Receipt, error)
+}
@@ -2,20 +2,30 @@ package splitbot
 
 import (
 	"context"
+	"fmt"
 	"os"
 
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/receipt"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	"github.com/naseer2426/split-bot/internal/splitbot/tools"
 	"github.com/tmc/langchaingo/agents"
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/memory"
+	lctools "github.com/tmc/langchaingo/tools"
 )
 
 const (
-	splitBotModel        = "x-ai/grok-4-fast"
-	splitBotSystemPrompt = `Assistant is designed to be able to assist with a wide range of tasks, from answering simple questions to providing in-depth explanations and discussions on a wide range of topics. As a language model, Assistant is able to generate human-like text based on the input it receives, allowing it to engage in natural-sounding conversations and provide responses that are coherent and relevant to the topic at hand.
+	splitBotModel = "x-ai/grok-4-fast"
+	// conversationHistoryLimit is the number of past turns loaded into the
+	// agent's memory for a chat on each invocation.
+	conversationHistoryLimit = 20
+	splitBotSystemPrompt     = `Assistant is SplitBot, a Telegram assistant that helps a group of people track shared expenses, see who owes who, and settle up. Members add expenses in plain English or by uploading a receipt photo, and Assistant figures out who paid, how to split the cost, and keeps a running balance per person for this chat.
 
-Assistant is constantly learning and improving, and its capabilities are constantly evolving. It is able to process and understand large amounts of text, and can use this knowledge to provide accurate and informative responses to a wide range of questions. Additionally, Assistant is able to generate its own text based on the input it receives, allowing it to engage in discussions and provide explanations and descriptions on a wide range of topics.
+When a member describes an expense, use add_expense to record it rather than just replying with the math. When asked for tips, taxes, or per-person shares, use the calculator. When asked who owes who, use list_balances. When a member says they've paid someone back, use settle_up. When a receipt has been OCR'd, use parse_receipt to get structured line items before proposing a split.
 
-Overall, Assistant is a powerful tool that can help with a wide range of tasks and provide valuable insights and information on a wide range of topics. Whether you need help with a specific question or just want to have a conversation about a particular topic, Assistant is here to assist.
+Always prefer using a tool over answering from memory when the question is about this chat's expenses or balances, since those only exist in the database, not in what Assistant was trained on.
 
 TOOLS:
 ------
@@ -26,8 +36,25 @@ Assistant has access to the following tools:
 `
 )
 
+// splitBotTools returns the toolset available to the agent. The same set
+// is shared across chats; tools scope themselves to the current chat via
+// chatctx.
+func splitBotTools(receiptParser receipt.Parser) []lctools.Tool {
+	return []lctools.Tool{
+		&tools.CalculatorTool{},
+		&tools.AddExpenseTool{},
+		&tools.ListBalancesTool{},
+		&tools.SettleUpTool{},
+		tools.NewParseReceiptTool(receiptParser),
+	}
+}
+
+// Bot runs the conversational agent for every chat. The LLM and tools are
+// shared across chats; conversation memory is scoped per chat and built
+// fresh for each message so concurrent chats don't share history.
 type Bot struct {
-	executor *agents.Executor
+	llm           *openai.LLM
+	receiptParser receipt.Parser
 }
 
 func NewBot() *Bot {
@@ -39,24 +66,48 @@ func NewBot() *Bot {
 	if err != nil {
 		panic(err)
 	}
-	agent := agents.NewConversationalAgent( //TODO: check if this agent is the correct one to use
-		llm,
-		nil, //TODO: add tools here: calculator, google sheets
-		agents.WithPromptPrefix(splitBotSystemPrompt), //TODO: update this for system prompt
-	)
-	exectutor := agents.NewExecutor(
-		agent,
-		//TODO: create your own memory and add it here
-	)
+
+	var receiptParser receipt.Parser
+	if os.Getenv("RECEIPT_PARSER") == "llm" {
+		receiptParser = receipt.NewLLMParser(llm)
+	} else {
+		receiptParser = receipt.NewMarkdownParser()
+	}
+
 	return &Bot{
-		executor: exectutor,
+		llm:           llm,
+		receiptParser: receiptParser,
 	}
 }
 
+// HandleMessage runs the conversational agent on a plain-text message.
+// Receipt images are not handled here: they go through ParseReceipt and
+// ProposeSplit instead, so the user confirms participants and split mode
+// on an inline keyboard rather than the agent guessing at them.
 func (b *Bot) HandleMessage(requestID string, message *Message) (string, error) {
-	if message.Image != nil {
-		return message.Image.ExtractedText, nil
+	chat, err := db.GetOrCreateChat(message.ChatID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve chat: %w", err)
 	}
-	ctx := context.Background()
-	return chains.Run(ctx, b.executor, message.Text)
+
+	agent := agents.NewConversationalAgent(
+		b.llm,
+		splitBotTools(b.receiptParser),
+		agents.WithPromptPrefix(splitBotSystemPrompt),
+	)
+	executor := agents.NewExecutor(
+		agent,
+		agents.WithMemory(memory.NewConversationBuffer(
+			memory.WithChatHistory(NewPostgresChatHistory(chat.ID)),
+		)),
+	)
+
+	ctx := chatctx.WithChatID(context.Background(), chat.ID)
+	return chains.Run(ctx, executor, message.Text)
+}
+
+// ParseReceipt parses OCR'd receipt text into a structured Receipt, using
+// whichever receipt.Parser the bot was configured with.
+func (b *Bot) ParseReceipt(requestID string, ocrText string) (*receipt.Receipt, error) {
+	return b.receiptParser.Parse(requestID, ocrText)
 }
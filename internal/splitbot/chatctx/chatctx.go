@@ -0,0 +1,23 @@
+// Package chatctx carries the current chat's internal ID through a
+// context.Context so tools invoked by the agent can scope their reads and
+// writes to the chat the message came from, without every tool needing a
+// reference back to the splitbot package.
+package chatctx
+
+import "context"
+
+type contextKey struct{}
+
+var chatIDKey = contextKey{}
+
+// WithChatID returns a context carrying the given internal chat ID.
+func WithChatID(ctx context.Context, chatID uint) context.Context {
+	return context.WithValue(ctx, chatIDKey, chatID)
+}
+
+// ChatID returns the internal chat ID stored in ctx, or false if none is
+// present.
+func ChatID(ctx context.Context) (uint, bool) {
+	chatID, ok := ctx.Value(chatIDKey).(uint)
+	return chatID, ok
+}
@@ -0,0 +1,283 @@
+package splitbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	"github.com/naseer2426/split-bot/internal/splitbot/tools"
+	"github.com/naseer2426/split-bot/internal/telegram"
+)
+
+const (
+	defaultHistoryLimit = 10
+	maxHistoryLimit     = 50
+)
+
+// CommandResult is the reply to a slash command: either plain text, a
+// file to send back as a document (e.g. /export), or a refresh of an
+// in-progress split's confirmation message (e.g. /addsplit).
+type CommandResult struct {
+	Text         string
+	Document     *CommandDocument
+	PendingSplit *PendingSplitRefresh
+}
+
+// PendingSplitRefresh tells the caller to re-render a split confirmation
+// message in place, since the command changed its state (e.g. added a
+// participant) rather than just replying to the chat.
+type PendingSplitRefresh struct {
+	MessageID int
+	Text      string
+	Keyboard  *telegram.InlineKeyboardMarkup
+}
+
+// CommandDocument is a file to send to the chat as the result of a
+// command.
+type CommandDocument struct {
+	Filename string
+	Content  []byte
+}
+
+// IsCommand reports whether text is a slash command (e.g. "/balance" or
+// "/settle@SplitBot @bob 12.50") rather than a natural-language message
+// for the LLM agent.
+func IsCommand(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), "/")
+}
+
+// HandleCommand dispatches a slash command deterministically, without
+// going through the LLM agent, so the common cases stay cheap and
+// predictable.
+func (b *Bot) HandleCommand(requestID string, message *Message) (*CommandResult, error) {
+	name, args := parseCommand(message.Text)
+
+	chat, err := db.GetOrCreateChat(message.ChatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve chat: %w", err)
+	}
+
+	switch name {
+	case "start":
+		return cmdStart(message)
+	case "help":
+		return cmdHelp(), nil
+	case "groupid":
+		return &CommandResult{Text: fmt.Sprintf("Chat ID: %d", message.ChatID)}, nil
+	case "balance":
+		return cmdBalance(chat.ID)
+	case "settle":
+		return cmdSettle(chat.ID, message.From.Username, args)
+	case "history":
+		return cmdHistory(chat.ID, args)
+	case "export":
+		return cmdExport(chat.ID, args)
+	case "addsplit":
+		return b.cmdAddSplit(message.ChatID, args)
+	default:
+		return &CommandResult{Text: fmt.Sprintf("Unknown command /%s. Send /help to see what I can do.", name)}, nil
+	}
+}
+
+// parseCommand splits a message like "/settle@SplitBot @bob 12.50" into
+// its command name ("settle", lowercased, with any "@botname" suffix
+// stripped) and the remaining whitespace-separated arguments.
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	name := strings.TrimPrefix(fields[0], "/")
+	if at := strings.Index(name, "@"); at != -1 {
+		name = name[:at]
+	}
+	return strings.ToLower(name), fields[1:]
+}
+
+func cmdStart(message *Message) (*CommandResult, error) {
+	if message.From.Username != "" {
+		if _, err := db.GetOrCreateUser(message.From.Username); err != nil {
+			return nil, err
+		}
+	}
+	return &CommandResult{Text: "Hey, I'm SplitBot! Add me to a group and tell me about expenses in plain English, or upload a receipt photo and I'll walk you through splitting it. Send /help to see the available commands."}, nil
+}
+
+func cmdHelp() *CommandResult {
+	return &CommandResult{Text: `Commands:
+/start - say hello and register with the bot
+/help - show this message
+/groupid - show this chat's ID
+/balance - show each person's net balance
+/settle @user amount - record a payment to clear a balance
+/history [n] - show the last n expenses (default 10)
+/export csv|json - download this chat's expenses as a file
+/addsplit @user - add someone to the split awaiting confirmation`}
+}
+
+func cmdBalance(chatID uint) (*CommandResult, error) {
+	ctx := chatctx.WithChatID(context.Background(), chatID)
+	text, err := (&tools.ListBalancesTool{}).Call(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return &CommandResult{Text: text}, nil
+}
+
+func cmdSettle(chatID uint, from string, args []string) (*CommandResult, error) {
+	if len(args) < 2 {
+		return &CommandResult{Text: "Usage: /settle @user amount"}, nil
+	}
+
+	to := strings.TrimPrefix(args[0], "@")
+	amount, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || amount <= 0 {
+		return &CommandResult{Text: "Amount must be a positive number, e.g. /settle @bob 12.50"}, nil
+	}
+
+	input, err := json.Marshal(struct {
+		From     string  `json:"from"`
+		To       string  `json:"to"`
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	}{From: from, To: to, Amount: amount, Currency: "USD"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settle_up input: %w", err)
+	}
+
+	ctx := chatctx.WithChatID(context.Background(), chatID)
+	text, err := (&tools.SettleUpTool{}).Call(ctx, string(input))
+	if err != nil {
+		return nil, err
+	}
+	return &CommandResult{Text: text}, nil
+}
+
+// cmdAddSplit adds a participant to the chat's active pending split, for
+// a member who wasn't offered as a toggle because they've never appeared
+// in this chat before. This is the only way to split a group's very
+// first expense with someone other than the uploader.
+func (b *Bot) cmdAddSplit(chatID int64, args []string) (*CommandResult, error) {
+	if len(args) != 1 {
+		return &CommandResult{Text: "Usage: /addsplit @user"}, nil
+	}
+	username := strings.TrimPrefix(args[0], "@")
+
+	text, keyboard, messageID, err := b.AddParticipant(chatID, username)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveSplit) {
+			return &CommandResult{Text: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	return &CommandResult{
+		Text: fmt.Sprintf("Added %s to the split.", username),
+		PendingSplit: &PendingSplitRefresh{
+			MessageID: messageID,
+			Text:      text,
+			Keyboard:  keyboard,
+		},
+	}, nil
+}
+
+func cmdHistory(chatID uint, args []string) (*CommandResult, error) {
+	limit := defaultHistoryLimit
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return &CommandResult{Text: "Usage: /history [n]"}, nil
+		}
+		limit = n
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	var expenses []db.Expense
+	if err := db.GetDB().Preload("Payer").Preload("Participants.User").
+		Where("chat_id = ?", chatID).
+		Order("id desc").Limit(limit).Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load expense history: %w", err)
+	}
+	if len(expenses) == 0 {
+		return &CommandResult{Text: "No expenses recorded for this chat yet."}, nil
+	}
+
+	text := fmt.Sprintf("Last %d expense(s):\n", len(expenses))
+	for i := len(expenses) - 1; i >= 0; i-- {
+		expense := expenses[i]
+		text += fmt.Sprintf("- %s %.2f paid by %s for %q\n", expense.Currency, expense.Amount, expense.Payer.Username, expense.Description)
+	}
+	return &CommandResult{Text: text}, nil
+}
+
+func cmdExport(chatID uint, args []string) (*CommandResult, error) {
+	format := "csv"
+	if len(args) > 0 {
+		format = strings.ToLower(args[0])
+	}
+	if format != "csv" && format != "json" {
+		return &CommandResult{Text: "Usage: /export csv|json"}, nil
+	}
+
+	var expenses []db.Expense
+	if err := db.GetDB().Preload("Payer").Preload("Participants.User").
+		Where("chat_id = ?", chatID).Order("id asc").Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load expenses: %w", err)
+	}
+
+	if format == "json" {
+		content, err := json.MarshalIndent(expenses, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode expenses: %w", err)
+		}
+		return &CommandResult{Document: &CommandDocument{Filename: "expenses.json", Content: content}}, nil
+	}
+
+	content, err := expensesToCSV(expenses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode expenses: %w", err)
+	}
+	return &CommandResult{Document: &CommandDocument{Filename: "expenses.csv", Content: content}}, nil
+}
+
+// expensesToCSV renders one row per expense participant, so each row is a
+// single person's share of a single expense.
+func expensesToCSV(expenses []db.Expense) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"description", "payer", "amount", "currency", "participant", "share"}); err != nil {
+		return nil, err
+	}
+	for _, expense := range expenses {
+		for _, participant := range expense.Participants {
+			row := []string{
+				expense.Description,
+				expense.Payer.Username,
+				fmt.Sprintf("%.2f", expense.Amount),
+				expense.Currency,
+				participant.User.Username,
+				fmt.Sprintf("%.2f", participant.Share),
+			}
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,15 +1,9 @@
 package splitbot
 
 type Message struct {
-	Text  string
-	Image *Image
-	From  User
-}
-
-type Image struct {
-	Url           string
-	FileID        string
-	ExtractedText string
+	ChatID int64
+	Text   string
+	From   User
 }
 
 type User struct {
@@ -0,0 +1,93 @@
+package splitbot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/tmc/langchaingo/llms"
+	"gorm.io/gorm"
+)
+
+// PostgresChatHistory is a langchaingo memory.ChatMessageHistory backed by
+// the ConversationMessage table, scoped to a single Telegram chat so the
+// agent remembers prior turns across webhook invocations.
+type PostgresChatHistory struct {
+	db     *gorm.DB
+	chatID uint
+}
+
+// NewPostgresChatHistory returns a chat history for the given internal
+// chat row ID. Use db.GetOrCreateChat to resolve a Telegram chat ID first.
+func NewPostgresChatHistory(chatID uint) *PostgresChatHistory {
+	return &PostgresChatHistory{
+		db:     db.GetDB(),
+		chatID: chatID,
+	}
+}
+
+// AddMessage implements memory.ChatMessageHistory.
+func (h *PostgresChatHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	role := "human"
+	if message.GetType() == llms.ChatMessageTypeAI {
+		role = "ai"
+	}
+	return h.db.WithContext(ctx).Create(&db.ConversationMessage{
+		ChatID:  h.chatID,
+		Role:    role,
+		Content: message.GetContent(),
+	}).Error
+}
+
+// AddUserMessage implements memory.ChatMessageHistory.
+func (h *PostgresChatHistory) AddUserMessage(ctx context.Context, message string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: message})
+}
+
+// AddAIMessage implements memory.ChatMessageHistory.
+func (h *PostgresChatHistory) AddAIMessage(ctx context.Context, message string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: message})
+}
+
+// Clear implements memory.ChatMessageHistory.
+func (h *PostgresChatHistory) Clear(ctx context.Context) error {
+	return h.db.WithContext(ctx).Where("chat_id = ?", h.chatID).Delete(&db.ConversationMessage{}).Error
+}
+
+// Messages implements memory.ChatMessageHistory, returning the last
+// conversationHistoryLimit turns for the chat in chronological order.
+func (h *PostgresChatHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	var rows []db.ConversationMessage
+	if err := h.db.WithContext(ctx).
+		Where("chat_id = ?", h.chatID).
+		Order("id desc").
+		Limit(conversationHistoryLimit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load conversation history: %w", err)
+	}
+
+	messages := make([]llms.ChatMessage, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		if row.Role == "ai" {
+			messages = append(messages, llms.AIChatMessage{Content: row.Content})
+		} else {
+			messages = append(messages, llms.HumanChatMessage{Content: row.Content})
+		}
+	}
+	return messages, nil
+}
+
+// SetMessages implements memory.ChatMessageHistory by replacing the
+// chat's history with the given messages.
+func (h *PostgresChatHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	if err := h.Clear(ctx); err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if err := h.AddMessage(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package splitbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/receipt"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	"github.com/naseer2426/split-bot/internal/splitbot/tools"
+	"github.com/naseer2426/split-bot/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// pendingSplitMode is the only split strategy a PendingSplit currently
+// supports: the amount divided evenly between the selected participants.
+// By-item and per-person-percentage allocation are a follow-up; there is
+// no keyboard button for them until they're implemented, so a confirmed
+// split never claims to be something it isn't.
+const pendingSplitMode = "equal"
+
+// ProposeSplit records a PendingSplit for a parsed receipt and returns the
+// confirmation text and inline keyboard to send. The uploader is
+// pre-selected as the payer and sole confirmed participant; every other
+// user seen before in this chat is offered as a toggle so they can be
+// added before Confirm.
+func (b *Bot) ProposeSplit(requestID string, chatID int64, from User, parsed *receipt.Receipt) (string, *telegram.InlineKeyboardMarkup, uint, error) {
+	chat, err := db.GetOrCreateChat(chatID)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to resolve chat: %w", err)
+	}
+	payer, err := db.GetOrCreateUser(from.Username)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	candidates := map[string]bool{payer.Username: true}
+	knownUsers, err := db.ListKnownChatUsers(chat.ID)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to list known chat users: %w", err)
+	}
+	for _, user := range knownUsers {
+		if _, ok := candidates[user.Username]; !ok {
+			candidates[user.Username] = false
+		}
+	}
+
+	participants, err := encodeParticipants(candidates)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	amount := parsed.Total
+	if amount == 0 {
+		amount = parsed.Subtotal + parsed.Tax + parsed.Tip
+	}
+	if amount <= 0 {
+		return "", nil, 0, fmt.Errorf("could not find a total on this receipt, please enter the amount manually")
+	}
+
+	pending := db.PendingSplit{
+		ChatID:       chat.ID,
+		CreatedByID:  payer.ID,
+		Payer:        payer.Username,
+		Description:  parsed.Merchant,
+		Amount:       amount,
+		Currency:     parsed.Currency,
+		SplitMode:    pendingSplitMode,
+		Participants: participants,
+	}
+	if err := db.GetDB().Create(&pending).Error; err != nil {
+		return "", nil, 0, fmt.Errorf("failed to create pending split: %w", err)
+	}
+
+	text, keyboard := renderPendingSplit(&pending)
+	return text, keyboard, pending.ID, nil
+}
+
+// SetPendingSplitMessageID records which Telegram message is showing a
+// pending split's confirmation keyboard, so a later button press on that
+// message can be traced back to it.
+func (b *Bot) SetPendingSplitMessageID(pendingSplitID uint, messageID int) error {
+	return db.GetDB().Model(&db.PendingSplit{}).
+		Where("id = ?", pendingSplitID).
+		Update("message_id", messageID).Error
+}
+
+// HandleCallback applies an inline keyboard button press to the pending
+// split it belongs to (found by the message the keyboard is attached to)
+// and returns the updated confirmation text and keyboard. Once the user
+// taps Confirm, the expense is committed and the third return value is
+// true, signalling that the keyboard should be removed.
+func (b *Bot) HandleCallback(requestID string, cbq *telegram.CallbackQuery) (string, *telegram.InlineKeyboardMarkup, bool, error) {
+	chat, err := db.GetOrCreateChat(cbq.Message.Chat.ID)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to resolve chat: %w", err)
+	}
+
+	var pending db.PendingSplit
+	if err := db.GetDB().Where("chat_id = ? AND message_id = ?", chat.ID, cbq.Message.MessageID).
+		First(&pending).Error; err != nil {
+		return "", nil, false, fmt.Errorf("failed to load pending split: %w", err)
+	}
+	if pending.Confirmed {
+		text, _ := renderPendingSplit(&pending)
+		return text, &telegram.InlineKeyboardMarkup{}, true, nil
+	}
+
+	action, arg, _ := strings.Cut(cbq.Data, ":")
+	switch action {
+	case "toggle":
+		if err := togglePendingParticipant(&pending, arg); err != nil {
+			return "", nil, false, err
+		}
+	case "confirm":
+		if err := b.confirmPendingSplit(&pending); err != nil {
+			return "", nil, false, err
+		}
+		text, _ := renderPendingSplit(&pending)
+		return text, &telegram.InlineKeyboardMarkup{}, true, nil
+	default:
+		return "", nil, false, fmt.Errorf("unknown callback action: %s", action)
+	}
+
+	if err := db.GetDB().Save(&pending).Error; err != nil {
+		return "", nil, false, fmt.Errorf("failed to save pending split: %w", err)
+	}
+
+	text, keyboard := renderPendingSplit(&pending)
+	return text, keyboard, false, nil
+}
+
+// ErrNoActiveSplit is returned by AddParticipant when a chat has no split
+// awaiting confirmation to add a participant to.
+var ErrNoActiveSplit = errors.New("no split is awaiting confirmation in this chat")
+
+// AddParticipant adds username as an included participant on the chat's
+// active pending split, for members who weren't offered as a toggle
+// because they've never appeared in this chat before (e.g. a group's very
+// first expense). It returns the split's message ID along with the
+// updated confirmation text and keyboard, so the caller can refresh the
+// original prompt in place.
+func (b *Bot) AddParticipant(chatID int64, username string) (string, *telegram.InlineKeyboardMarkup, int, error) {
+	chat, err := db.GetOrCreateChat(chatID)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to resolve chat: %w", err)
+	}
+	if _, err := db.GetOrCreateUser(username); err != nil {
+		return "", nil, 0, err
+	}
+
+	pending, err := db.FindActivePendingSplit(chat.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, 0, ErrNoActiveSplit
+		}
+		return "", nil, 0, fmt.Errorf("failed to load pending split: %w", err)
+	}
+
+	participants, err := decodeParticipants(pending.Participants)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	participants[username] = true
+
+	encoded, err := encodeParticipants(participants)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	pending.Participants = encoded
+	if err := db.GetDB().Save(pending).Error; err != nil {
+		return "", nil, 0, fmt.Errorf("failed to save pending split: %w", err)
+	}
+
+	text, keyboard := renderPendingSplit(pending)
+	return text, keyboard, pending.MessageID, nil
+}
+
+func togglePendingParticipant(pending *db.PendingSplit, username string) error {
+	participants, err := decodeParticipants(pending.Participants)
+	if err != nil {
+		return err
+	}
+	participants[username] = !participants[username]
+
+	encoded, err := encodeParticipants(participants)
+	if err != nil {
+		return err
+	}
+	pending.Participants = encoded
+	return nil
+}
+
+func encodeParticipants(participants map[string]bool) (string, error) {
+	encoded, err := json.Marshal(participants)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode participants: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func decodeParticipants(encoded string) (map[string]bool, error) {
+	participants := map[string]bool{}
+	if encoded == "" {
+		return participants, nil
+	}
+	if err := json.Unmarshal([]byte(encoded), &participants); err != nil {
+		return nil, fmt.Errorf("failed to decode participants: %w", err)
+	}
+	return participants, nil
+}
+
+// confirmPendingSplit commits the expense through AddExpenseTool, the
+// same tool the agent uses, so a split confirmed via the keyboard is
+// recorded identically to one the agent records from a typed message.
+func (b *Bot) confirmPendingSplit(pending *db.PendingSplit) error {
+	participants, err := decodeParticipants(pending.Participants)
+	if err != nil {
+		return err
+	}
+
+	type participantInput struct {
+		Username string  `json:"username"`
+		Weight   float64 `json:"weight"`
+	}
+	var selected []participantInput
+	for username, included := range participants {
+		if included {
+			selected = append(selected, participantInput{Username: username, Weight: 1})
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no participants selected")
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Username < selected[j].Username })
+
+	input, err := json.Marshal(struct {
+		Payer        string             `json:"payer"`
+		Description  string             `json:"description"`
+		Amount       float64            `json:"amount"`
+		Currency     string             `json:"currency"`
+		Participants []participantInput `json:"participants"`
+	}{
+		Payer:        pending.Payer,
+		Description:  pending.Description,
+		Amount:       pending.Amount,
+		Currency:     pending.Currency,
+		Participants: selected,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode add_expense input: %w", err)
+	}
+
+	ctx := chatctx.WithChatID(context.Background(), pending.ChatID)
+	addExpense := &tools.AddExpenseTool{}
+	if _, err := addExpense.Call(ctx, string(input)); err != nil {
+		return fmt.Errorf("failed to record expense: %w", err)
+	}
+
+	pending.Confirmed = true
+	return db.GetDB().Save(pending).Error
+}
+
+// renderPendingSplit builds the confirmation text and inline keyboard for
+// a pending split: a checkbox per candidate participant and a final
+// Confirm button. Once confirmed, the keyboard is nil and the text reads
+// as a receipt rather than a prompt.
+func renderPendingSplit(pending *db.PendingSplit) (string, *telegram.InlineKeyboardMarkup) {
+	participants, _ := decodeParticipants(pending.Participants)
+	usernames := make([]string, 0, len(participants))
+	for username := range participants {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	if pending.Confirmed {
+		text := fmt.Sprintf("Recorded %s %.2f for %q, split %s between:\n", pending.Currency, pending.Amount, pending.Description, pending.SplitMode)
+		for _, username := range usernames {
+			if participants[username] {
+				text += fmt.Sprintf("- %s\n", username)
+			}
+		}
+		return text, nil
+	}
+
+	text := fmt.Sprintf("Split %s %.2f for %q (%s split):\n", pending.Currency, pending.Amount, pending.Description, pending.SplitMode)
+	var rows [][]telegram.InlineKeyboardButton
+	for _, username := range usernames {
+		label := "⬜ " + username
+		if participants[username] {
+			label = "✅ " + username
+		}
+		text += fmt.Sprintf("- %s: %s\n", username, participantStatus(participants[username]))
+		rows = append(rows, []telegram.InlineKeyboardButton{{
+			Text:         label,
+			CallbackData: "toggle:" + username,
+		}})
+	}
+	rows = append(rows, []telegram.InlineKeyboardButton{{
+		Text:         "✅ Confirm",
+		CallbackData: "confirm:",
+	}})
+
+	return text, &telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func participantStatus(included bool) string {
+	if included {
+		return "included"
+	}
+	return "excluded"
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	lctools "github.com/tmc/langchaingo/tools"
+)
+
+var _ lctools.Tool = &AddExpenseTool{}
+
+// AddExpenseTool records a shared expense for the current chat, split
+// between the given participants according to their weights (equal by
+// default).
+type AddExpenseTool struct{}
+
+type addExpenseParticipant struct {
+	Username string  `json:"username"`
+	Weight   float64 `json:"weight"`
+}
+
+type addExpenseInput struct {
+	Payer        string                  `json:"payer"`
+	Description  string                  `json:"description"`
+	Amount       float64                 `json:"amount"`
+	Currency     string                  `json:"currency"`
+	Participants []addExpenseParticipant `json:"participants"`
+}
+
+func (t *AddExpenseTool) Name() string {
+	return "add_expense"
+}
+
+func (t *AddExpenseTool) Description() string {
+	return `Records a new shared expense in the current chat.
+Input must be JSON matching: {"payer": "username", "description": "dinner", "amount": 120.50, "currency": "USD", "participants": [{"username": "alice", "weight": 1}, {"username": "bob", "weight": 1}]}.
+Participants' "weight" defaults to 1 (equal split) if omitted; a weight of 2 gets double the share of a weight of 1.
+Returns a confirmation with each participant's share.`
+}
+
+func (t *AddExpenseTool) Call(ctx context.Context, input string) (string, error) {
+	chatID, ok := chatctx.ChatID(ctx)
+	if !ok {
+		return "", errNoChat
+	}
+
+	var in addExpenseInput
+	if err := json.Unmarshal([]byte(input), &in); err != nil {
+		return "", fmt.Errorf("invalid input, expected JSON: %w", err)
+	}
+	if in.Payer == "" || in.Amount <= 0 || len(in.Participants) == 0 {
+		return "", fmt.Errorf("payer, amount, and at least one participant are required")
+	}
+	if in.Currency == "" {
+		in.Currency = "USD"
+	}
+
+	payer, err := db.GetOrCreateUser(in.Payer)
+	if err != nil {
+		return "", err
+	}
+
+	totalWeight := 0.0
+	for _, p := range in.Participants {
+		weight := p.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		totalWeight += weight
+	}
+
+	expense := db.Expense{
+		ChatID:      chatID,
+		PayerID:     payer.ID,
+		Description: in.Description,
+		Amount:      in.Amount,
+		Currency:    in.Currency,
+	}
+	for _, p := range in.Participants {
+		weight := p.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		user, err := db.GetOrCreateUser(p.Username)
+		if err != nil {
+			return "", err
+		}
+		expense.Participants = append(expense.Participants, db.ExpenseParticipant{
+			UserID: user.ID,
+			Weight: weight,
+			Share:  in.Amount * weight / totalWeight,
+		})
+	}
+
+	if err := db.GetDB().Create(&expense).Error; err != nil {
+		return "", fmt.Errorf("failed to record expense: %w", err)
+	}
+
+	result := fmt.Sprintf("Recorded %s %.2f paid by %s for %q, split:\n", in.Currency, in.Amount, in.Payer, in.Description)
+	for i, p := range in.Participants {
+		result += fmt.Sprintf("- %s owes %.2f %s\n", p.Username, expense.Participants[i].Share, in.Currency)
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lctools "github.com/tmc/langchaingo/tools"
+)
+
+var _ lctools.Tool = &CalculatorTool{}
+
+// CalculatorTool evaluates arithmetic expressions, including the
+// percentage-of math needed for tip/tax splitting, e.g.
+// "45.50 + 45.50 * 0.18" or "20% of 136".
+type CalculatorTool struct{}
+
+func (c *CalculatorTool) Name() string {
+	return "calculator"
+}
+
+func (c *CalculatorTool) Description() string {
+	return `Useful for doing arithmetic, including computing tips, taxes, and per-person shares.
+Input should be a plain arithmetic expression such as "136.40 * 1.18" or "20% of 136.40".
+Returns the numeric result as a string.`
+}
+
+func (c *CalculatorTool) Call(_ context.Context, input string) (string, error) {
+	expr, err := normalizePercentOf(input)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := evalArithmetic(expr)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate %q: %w", input, err)
+	}
+	return strconv.FormatFloat(result, 'f', -1, 64), nil
+}
+
+// percentOfRE matches "X% of Y" (and "X % of Y"), e.g. "20% of 136.40".
+var percentOfRE = regexp.MustCompile(`^\s*([\d.]+)\s*%\s*of\s*(.+?)\s*$`)
+
+// normalizePercentOf rewrites "X% of Y" into "Y * X / 100" so it can be
+// evaluated as a normal arithmetic expression.
+func normalizePercentOf(input string) (string, error) {
+	if match := percentOfRE.FindStringSubmatch(strings.ToLower(input)); match != nil {
+		percent, base := match[1], match[2]
+		return fmt.Sprintf("(%s) * (%s) / 100", base, percent), nil
+	}
+	return input, nil
+}
+
+// evalArithmetic evaluates a basic +,-,*,/ expression using the Go
+// expression parser, which gives us operator precedence and parentheses
+// for free without pulling in an external dependency.
+func evalArithmetic(expr string) (float64, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return 0, err
+	}
+	return evalNode(node)
+}
+
+func evalNode(node ast.Expr) (float64, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return 0, fmt.Errorf("unsupported literal: %s", n.Value)
+		}
+		return strconv.ParseFloat(n.Value, 64)
+	case *ast.ParenExpr:
+		return evalNode(n.X)
+	case *ast.UnaryExpr:
+		val, err := evalNode(n.X)
+		if err != nil {
+			return 0, err
+		}
+		if n.Op == token.SUB {
+			return -val, nil
+		}
+		return val, nil
+	case *ast.BinaryExpr:
+		left, err := evalNode(n.X)
+		if err != nil {
+			return 0, err
+		}
+		right, err := evalNode(n.Y)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return left + right, nil
+		case token.SUB:
+			return left - right, nil
+		case token.MUL:
+			return left * right, nil
+		case token.QUO:
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return left / right, nil
+		default:
+			return 0, fmt.Errorf("unsupported operator: %s", n.Op)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported expression")
+	}
+}
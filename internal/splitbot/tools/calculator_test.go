@@ -0,0 +1,58 @@
+package tools
+
+import "testing"
+
+func TestNormalizePercentOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"simple percent of", "20% of 136.40", "(136.40) * (20) / 100"},
+		{"space before percent sign", "20 % of 136.40", "(136.40) * (20) / 100"},
+		{"uppercase OF", "15% OF 50", "(50) * (15) / 100"},
+		{"not a percent-of expression", "45.50 + 45.50 * 0.18", "45.50 + 45.50 * 0.18"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizePercentOf(tt.input)
+			if err != nil {
+				t.Fatalf("normalizePercentOf(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizePercentOf(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalArithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    float64
+		wantErr bool
+	}{
+		{"addition and multiplication with precedence", "45.50 + 45.50 * 0.18", 53.69, false},
+		{"parentheses", "(1 + 2) * 3", 9, false},
+		{"percent-of rewrite", "(136.40) * (20) / 100", 27.28, false},
+		{"division by zero", "1 / 0", 0, true},
+		{"invalid expression", "1 +", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalArithmetic(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalArithmetic(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := got - tt.want; diff > 0.001 || diff < -0.001 {
+				t.Errorf("evalArithmetic(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
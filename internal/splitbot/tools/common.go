@@ -0,0 +1,12 @@
+// Package tools provides the langchaingo tools.Tool implementations the
+// split-bot agent uses to manage shared expenses: doing arithmetic,
+// recording expenses, reporting balances, and settling up. Every tool is
+// scoped to the chat the current message came from via chatctx, so the
+// same Bot can safely serve many chats concurrently.
+package tools
+
+import "errors"
+
+// errNoChat is returned by tools when they're invoked outside of a
+// request that carries a chat ID in its context.
+var errNoChat = errors.New("no chat_id in context")
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	lctools "github.com/tmc/langchaingo/tools"
+)
+
+var _ lctools.Tool = &ListBalancesTool{}
+
+// ListBalancesTool reports each user's net balance in the current chat: a
+// positive balance means the group owes that user money, a negative
+// balance means that user owes the group.
+type ListBalancesTool struct{}
+
+func (t *ListBalancesTool) Name() string {
+	return "list_balances"
+}
+
+func (t *ListBalancesTool) Description() string {
+	return `Reports each participant's net balance for the current chat.
+Input is ignored; pass an empty string.
+A positive balance means the group owes that person money, a negative balance means they owe the group.`
+}
+
+func (t *ListBalancesTool) Call(ctx context.Context, _ string) (string, error) {
+	chatID, ok := chatctx.ChatID(ctx)
+	if !ok {
+		return "", errNoChat
+	}
+
+	balances, err := computeBalances(chatID)
+	if err != nil {
+		return "", err
+	}
+	if len(balances) == 0 {
+		return "No expenses recorded for this chat yet.", nil
+	}
+
+	usernames := make([]string, 0, len(balances))
+	for username := range balances {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	result := ""
+	for _, username := range usernames {
+		result += fmt.Sprintf("%s: %+.2f\n", username, balances[username])
+	}
+	return result, nil
+}
+
+// computeBalances returns each user's net balance (paid - owed, plus
+// settlements), keyed by username, for the given chat.
+func computeBalances(chatID uint) (map[string]float64, error) {
+	database := db.GetDB()
+
+	var expenses []db.Expense
+	if err := database.Preload("Payer").Preload("Participants.User").
+		Where("chat_id = ?", chatID).Find(&expenses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load expenses: %w", err)
+	}
+
+	var settlements []db.Settlement
+	if err := database.Preload("FromUser").Preload("ToUser").
+		Where("chat_id = ?", chatID).Find(&settlements).Error; err != nil {
+		return nil, fmt.Errorf("failed to load settlements: %w", err)
+	}
+
+	return balancesFromRecords(expenses, settlements), nil
+}
+
+// balancesFromRecords applies a chat's expenses and settlements to a
+// balance-per-username map: a payer is credited the full amount, each
+// participant is debited their share, and a settlement credits whoever
+// paid it and debits whoever received it. Split out from computeBalances
+// so the sign convention can be unit tested without a database.
+func balancesFromRecords(expenses []db.Expense, settlements []db.Settlement) map[string]float64 {
+	balances := make(map[string]float64)
+
+	for _, expense := range expenses {
+		balances[expense.Payer.Username] += expense.Amount
+		for _, participant := range expense.Participants {
+			balances[participant.User.Username] -= participant.Share
+		}
+	}
+
+	for _, settlement := range settlements {
+		balances[settlement.FromUser.Username] += settlement.Amount
+		balances[settlement.ToUser.Username] -= settlement.Amount
+	}
+
+	return balances
+}
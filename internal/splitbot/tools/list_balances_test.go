@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/naseer2426/split-bot/internal/db"
+)
+
+func TestBalancesFromRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		expenses    []db.Expense
+		settlements []db.Settlement
+		want        map[string]float64
+	}{
+		{
+			name: "payer credited, participants debited their share",
+			expenses: []db.Expense{
+				{
+					Payer:  db.User{Username: "alice"},
+					Amount: 30,
+					Participants: []db.ExpenseParticipant{
+						{User: db.User{Username: "alice"}, Share: 10},
+						{User: db.User{Username: "bob"}, Share: 10},
+						{User: db.User{Username: "carol"}, Share: 10},
+					},
+				},
+			},
+			want: map[string]float64{"alice": 20, "bob": -10, "carol": -10},
+		},
+		{
+			name: "settlement credits the payer and debits the receiver",
+			settlements: []db.Settlement{
+				{FromUser: db.User{Username: "bob"}, ToUser: db.User{Username: "alice"}, Amount: 10},
+			},
+			want: map[string]float64{"bob": 10, "alice": -10},
+		},
+		{
+			name: "expenses and settlements combine for the same user",
+			expenses: []db.Expense{
+				{
+					Payer:  db.User{Username: "alice"},
+					Amount: 20,
+					Participants: []db.ExpenseParticipant{
+						{User: db.User{Username: "alice"}, Share: 10},
+						{User: db.User{Username: "bob"}, Share: 10},
+					},
+				},
+			},
+			settlements: []db.Settlement{
+				{FromUser: db.User{Username: "bob"}, ToUser: db.User{Username: "alice"}, Amount: 10},
+			},
+			want: map[string]float64{"alice": 0, "bob": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := balancesFromRecords(tt.expenses, tt.settlements)
+			if len(got) != len(tt.want) {
+				t.Fatalf("balancesFromRecords() = %v, want %v", got, tt.want)
+			}
+			for username, want := range tt.want {
+				if got[username] != want {
+					t.Errorf("balance[%q] = %v, want %v", username, got[username], want)
+				}
+			}
+		})
+	}
+}
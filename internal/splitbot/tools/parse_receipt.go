@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/naseer2426/split-bot/internal/receipt"
+	lctools "github.com/tmc/langchaingo/tools"
+)
+
+var _ lctools.Tool = &ParseReceiptTool{}
+
+// ParseReceiptTool turns OCR markdown of a receipt into a structured
+// summary of line items the agent can propose a split against.
+type ParseReceiptTool struct {
+	parser receipt.Parser
+}
+
+// NewParseReceiptTool builds a ParseReceiptTool backed by the given
+// receipt.Parser.
+func NewParseReceiptTool(parser receipt.Parser) *ParseReceiptTool {
+	return &ParseReceiptTool{parser: parser}
+}
+
+func (t *ParseReceiptTool) Name() string {
+	return "parse_receipt"
+}
+
+func (t *ParseReceiptTool) Description() string {
+	return `Parses OCR markdown of a receipt into a structured JSON Receipt with merchant, currency, line items, subtotal, tax, tip, and total.
+Input should be the raw OCR markdown text.
+Returns the Receipt as JSON.`
+}
+
+func (t *ParseReceiptTool) Call(_ context.Context, input string) (string, error) {
+	parsed, err := t.parser.Parse("", input)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse receipt: %w", err)
+	}
+
+	result, err := json.Marshal(parsed)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode parsed receipt: %w", err)
+	}
+	return string(result), nil
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/naseer2426/split-bot/internal/db"
+	"github.com/naseer2426/split-bot/internal/splitbot/chatctx"
+	lctools "github.com/tmc/langchaingo/tools"
+)
+
+var _ lctools.Tool = &SettleUpTool{}
+
+// SettleUpTool records a payment from one user to another in the current
+// chat, reducing the balance between them.
+type SettleUpTool struct{}
+
+type settleUpInput struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (t *SettleUpTool) Name() string {
+	return "settle_up"
+}
+
+func (t *SettleUpTool) Description() string {
+	return `Records a payment made to settle part or all of a balance between two people.
+Input must be JSON matching: {"from": "alice", "to": "bob", "amount": 25.00, "currency": "USD"}, meaning "from" paid "to".
+Returns a confirmation of the recorded settlement.`
+}
+
+func (t *SettleUpTool) Call(ctx context.Context, input string) (string, error) {
+	chatID, ok := chatctx.ChatID(ctx)
+	if !ok {
+		return "", errNoChat
+	}
+
+	var in settleUpInput
+	if err := json.Unmarshal([]byte(input), &in); err != nil {
+		return "", fmt.Errorf("invalid input, expected JSON: %w", err)
+	}
+	if in.From == "" || in.To == "" || in.Amount <= 0 {
+		return "", fmt.Errorf("from, to, and a positive amount are required")
+	}
+	if in.Currency == "" {
+		in.Currency = "USD"
+	}
+
+	fromUser, err := db.GetOrCreateUser(in.From)
+	if err != nil {
+		return "", err
+	}
+	toUser, err := db.GetOrCreateUser(in.To)
+	if err != nil {
+		return "", err
+	}
+
+	settlement := db.Settlement{
+		ChatID:     chatID,
+		FromUserID: fromUser.ID,
+		ToUserID:   toUser.ID,
+		Amount:     in.Amount,
+		Currency:   in.Currency,
+		SettledAt:  time.Now(),
+	}
+	if err := db.GetDB().Create(&settlement).Error; err != nil {
+		return "", fmt.Errorf("failed to record settlement: %w", err)
+	}
+
+	return fmt.Sprintf("Recorded: %s paid %s %.2f %s.", in.From, in.To, in.Amount, in.Currency), nil
+}
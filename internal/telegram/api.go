@@ -1,14 +1,24 @@
 package telegram
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/url"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
+const (
+	longPollTimeoutSeconds = 30
+	longPollRetryDelay     = 5 * time.Second
+)
+
 type TelegramAPI struct {
 	token  string
 	client *resty.Client
@@ -19,40 +29,182 @@ func NewTelegramAPI(whPath string) *TelegramAPI {
 		token:  os.Getenv("TELEGRAM_BOT_TOKEN"),
 		client: resty.New(),
 	}
-	t.setWebhook(whPath)
+	if os.Getenv("TELEGRAM_MODE") != "longpoll" {
+		t.setWebhook(whPath)
+	}
 	return t
 }
 
-// SendMessage sends a message to a Telegram chat
-func (t *TelegramAPI) SendMessage(requestID string, chatID int64, text string) error {
-	token := t.token
-	if token == "" {
+// Do calls a Telegram Bot API method, POSTing payload as JSON, and
+// decodes the response's "result" field into out (which may be nil).
+// Every typed method on TelegramAPI is a thin wrapper around this, modeled
+// after how mature Go Telegram libraries structure their client.
+func (t *TelegramAPI) Do(requestID string, method string, payload any, out any) error {
+	if t.token == "" {
 		return fmt.Errorf("TELEGRAM_BOT_TOKEN is not set")
 	}
 
-	reply := SendMessageRequest{
-		ChatID: chatID,
-		Text:   text,
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
 	}
 
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", token)
+	reqURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", t.token, method)
 	resp, err := t.client.R().
 		SetHeader("Content-Type", "application/json").
 		SetHeader("X-Request-ID", requestID).
-		SetBody(reply).
-		Post(url)
-
+		SetBody(payload).
+		SetResult(&envelope).
+		Post(reqURL)
 	if err != nil {
-		return fmt.Errorf("http call to telegram failed: %w", err)
+		return fmt.Errorf("http call to telegram method %s failed: %w", method, err)
 	}
-
 	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-		return fmt.Errorf("telegram returned non-2xx status: %d", resp.StatusCode())
+		return fmt.Errorf("telegram method %s returned non-2xx status: %d", method, resp.StatusCode())
+	}
+	if !envelope.OK {
+		return fmt.Errorf("telegram method %s returned error: %s", method, envelope.Description)
 	}
 
+	if out != nil && len(envelope.Result) > 0 {
+		if err := json.Unmarshal(envelope.Result, out); err != nil {
+			return fmt.Errorf("failed to decode telegram method %s result: %w", method, err)
+		}
+	}
 	return nil
 }
 
+// SendMessageOption customizes a SendMessage call, e.g. WithParseMode or
+// WithReplyMarkup.
+type SendMessageOption func(*SendMessageRequest)
+
+// WithParseMode sets how Telegram should parse the message text, e.g.
+// "MarkdownV2" or "HTML".
+func WithParseMode(parseMode string) SendMessageOption {
+	return func(r *SendMessageRequest) { r.ParseMode = parseMode }
+}
+
+// WithReplyMarkup attaches an inline keyboard to the message.
+func WithReplyMarkup(markup InlineKeyboardMarkup) SendMessageOption {
+	return func(r *SendMessageRequest) { r.ReplyMarkup = &markup }
+}
+
+// SendMessage sends a message to a Telegram chat and returns the ID of
+// the sent message, so it can later be edited with EditMessageText.
+func (t *TelegramAPI) SendMessage(requestID string, chatID int64, text string, opts ...SendMessageOption) (int, error) {
+	req := SendMessageRequest{ChatID: chatID, Text: text}
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var result struct {
+		MessageID int `json:"message_id"`
+	}
+	if err := t.Do(requestID, "sendMessage", req, &result); err != nil {
+		return 0, err
+	}
+	return result.MessageID, nil
+}
+
+// EditMessageTextOption customizes an EditMessageText call.
+type EditMessageTextOption func(*EditMessageTextRequest)
+
+// WithEditParseMode sets how Telegram should parse the edited text.
+func WithEditParseMode(parseMode string) EditMessageTextOption {
+	return func(r *EditMessageTextRequest) { r.ParseMode = parseMode }
+}
+
+// WithEditReplyMarkup replaces the inline keyboard on the edited message.
+func WithEditReplyMarkup(markup InlineKeyboardMarkup) EditMessageTextOption {
+	return func(r *EditMessageTextRequest) { r.ReplyMarkup = &markup }
+}
+
+// EditMessageText edits the text of a previously sent message, used to
+// turn a "processing…" placeholder into the final result once it's
+// ready.
+func (t *TelegramAPI) EditMessageText(requestID string, chatID int64, messageID int, text string, opts ...EditMessageTextOption) error {
+	req := EditMessageTextRequest{ChatID: chatID, MessageID: messageID, Text: text}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	return t.Do(requestID, "editMessageText", req, nil)
+}
+
+// AnswerCallbackQuery acknowledges an inline keyboard button press. Until
+// this is called, Telegram shows a loading spinner on the pressed button.
+func (t *TelegramAPI) AnswerCallbackQuery(requestID string, callbackQueryID string, text string) error {
+	req := AnswerCallbackQueryRequest{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+	}
+	return t.Do(requestID, "answerCallbackQuery", req, nil)
+}
+
+// GetMe returns information about the bot itself.
+func (t *TelegramAPI) GetMe(requestID string) (*BotUser, error) {
+	var botUser BotUser
+	if err := t.Do(requestID, "getMe", struct{}{}, &botUser); err != nil {
+		return nil, err
+	}
+	return &botUser, nil
+}
+
+// SetMyCommands registers the bot's slash commands so Telegram shows
+// them in the client's command menu.
+func (t *TelegramAPI) SetMyCommands(requestID string, commands []BotCommand) error {
+	req := SetMyCommandsRequest{Commands: commands}
+	return t.Do(requestID, "setMyCommands", req, nil)
+}
+
+// SendPhoto uploads a photo to a chat as multipart/form-data, e.g. a
+// generated settlement summary chart or a QR code.
+func (t *TelegramAPI) SendPhoto(requestID string, chatID int64, filename string, photo io.Reader, caption string) (int, error) {
+	return t.sendFile(requestID, "sendPhoto", "photo", chatID, filename, photo, caption)
+}
+
+// SendDocument uploads a file to a chat as multipart/form-data, e.g. a
+// CSV export of a chat's expenses.
+func (t *TelegramAPI) SendDocument(requestID string, chatID int64, filename string, document io.Reader, caption string) (int, error) {
+	return t.sendFile(requestID, "sendDocument", "document", chatID, filename, document, caption)
+}
+
+func (t *TelegramAPI) sendFile(requestID string, method string, fieldName string, chatID int64, filename string, file io.Reader, caption string) (int, error) {
+	if t.token == "" {
+		return 0, fmt.Errorf("TELEGRAM_BOT_TOKEN is not set")
+	}
+
+	var envelope struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Result      struct {
+			MessageID int `json:"message_id"`
+		} `json:"result"`
+	}
+
+	reqURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", t.token, method)
+	resp, err := t.client.R().
+		SetHeader("X-Request-ID", requestID).
+		SetFormData(map[string]string{
+			"chat_id": strconv.FormatInt(chatID, 10),
+			"caption": caption,
+		}).
+		SetFileReader(fieldName, filename, file).
+		SetResult(&envelope).
+		Post(reqURL)
+	if err != nil {
+		return 0, fmt.Errorf("http call to telegram method %s failed: %w", method, err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return 0, fmt.Errorf("telegram method %s returned non-2xx status: %d", method, resp.StatusCode())
+	}
+	if !envelope.OK {
+		return 0, fmt.Errorf("telegram method %s returned error: %s", method, envelope.Description)
+	}
+
+	return envelope.Result.MessageID, nil
+}
+
 // GetImageUrl retrieves the URL for accessing an image file by its file_unique_id
 func (t *TelegramAPI) GetImageUrl(requestID, fileId string) (string, error) {
 	token := t.token
@@ -93,6 +245,41 @@ func (t *TelegramAPI) GetImageUrl(requestID, fileId string) (string, error) {
 	return imageURL, nil
 }
 
+// UpdateHandler processes a single incoming Update received via LongPoll.
+type UpdateHandler func(update Update)
+
+// LongPoll repeatedly calls getUpdates, invoking handler for each Update
+// received, until ctx is cancelled. This is an alternative to
+// setWebhook that lets the bot run behind NAT without a public URL;
+// select it with TELEGRAM_MODE=longpoll.
+func (t *TelegramAPI) LongPoll(ctx context.Context, handler UpdateHandler) error {
+	if t.token == "" {
+		return fmt.Errorf("TELEGRAM_BOT_TOKEN is not set")
+	}
+
+	offset := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var updates []Update
+		req := GetUpdatesRequest{Offset: offset, Timeout: longPollTimeoutSeconds}
+		if err := t.Do("", "getUpdates", req, &updates); err != nil {
+			log.Printf("long poll getUpdates failed, retrying: %v", err)
+			time.Sleep(longPollRetryDelay)
+			continue
+		}
+
+		for _, update := range updates {
+			handler(update)
+			offset = update.UpdateID + 1
+		}
+	}
+}
+
 func (t *TelegramAPI) setWebhook(whPath string) {
 	base := os.Getenv("BACKEND_URL")
 	if base == "" {
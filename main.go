@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,7 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/naseer2426/split-bot/internal/api"
-	"github.com/naseer2426/split-bot/internal/splitbot"
+	"github.com/naseer2426/split-bot/internal/db"
 	"github.com/naseer2426/split-bot/internal/telegram"
 )
 
@@ -20,12 +21,20 @@ func main() {
 		panic(err)
 	}
 	router := initRouter()
-	t := initTelegramWebhook()
-	// run migrations
-	// db.AutoMigrate()
+	t := api.NewTelegramWebhook()
+
+	if os.Getenv("AUTO_MIGRATE") == "true" {
+		log.Println("running database migrations")
+		db.AutoMigrate()
+	}
 
 	router.GET("/", api.HealthCheck)
-	router.POST("/telegram/webhook", t.TelegramWebhook)
+
+	if os.Getenv("TELEGRAM_MODE") == "longpoll" {
+		go runLongPoll(t)
+	} else {
+		router.POST("/telegram/webhook", t.TelegramWebhook)
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -36,6 +45,20 @@ func main() {
 	}
 }
 
+// runLongPoll runs the bot via getUpdates instead of a webhook, for
+// deployments without a public URL. Selected with TELEGRAM_MODE=longpoll.
+func runLongPoll(t *api.TelegramWebhook) {
+	err := t.TelegramAPI.LongPoll(context.Background(), func(update telegram.Update) {
+		requestID := fmt.Sprintf("longpoll-%d", update.UpdateID)
+		if _, err := t.HandleUpdate(requestID, &update); err != nil {
+			log.Printf("requestID=%s failed to handle update: %v", requestID, err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("long poll exited: %v", err)
+	}
+}
+
 func initEnv() error {
 	// Load environment variables from .env file if present
 	if err := godotenv.Load(); err != nil {
@@ -62,10 +85,3 @@ func initRouter() *gin.Engine {
 
 	return router
 }
-
-func initTelegramWebhook() *api.TelegramWebhook {
-	return &api.TelegramWebhook{
-		TelegramAPI: telegram.NewTelegramAPI(os.Getenv("TELEGRAM_BOT_TOKEN")),
-		SplitBot:    splitbot.NewBot(),
-	}
-}